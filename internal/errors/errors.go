@@ -47,6 +47,56 @@ type ChecksumMismatchError struct {
 
 type NoCompilerSelected struct{}
 
+type InvalidPragmaError struct {
+	Pragma string `json:"pragma"`
+	Reason string `json:"reason"`
+}
+
+type NoMatchingVersionError struct {
+	Constraint string `json:"constraint"`
+}
+
+type NotCachedError struct {
+	Url string `json:"url"`
+}
+
+type ChecksumChangedError struct {
+	Version  string `json:"version"`
+	Platform string `json:"platform"`
+	HashFunc string `json:"hash_func"`
+	Known    string `json:"known"`
+	Received string `json:"received"`
+}
+
+type ModifiedFileError struct {
+	Version  string `json:"version"`
+	File     string `json:"file"`
+	HashFunc string `json:"hash_func"`
+}
+
+type UnverifiableError struct {
+	Version string `json:"version"`
+}
+
+type NoManifestError struct {
+	Version string `json:"version"`
+}
+
+type SelfTestError struct {
+	Version string `json:"version"`
+	Reason  string `json:"reason"`
+}
+
+type UnsafeArchiveError struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+type LockTimeoutError struct {
+	Path    string `json:"path"`
+	Timeout string `json:"timeout"`
+}
+
 func (r *NotInstalledError) Error() string {
 	return fmt.Sprintf("Version '%s' not installed. Run `gsolc-select install %s`.", r.Version, r.Version)
 }
@@ -70,3 +120,43 @@ func (r *ChecksumMismatchError) Error() string {
 func (r *NoCompilerSelected) Error() string {
 	return fmt.Sprintf("No compiler version selected.")
 }
+
+func (r *NoMatchingVersionError) Error() string {
+	return fmt.Sprintf("No version matches '%s'.", r.Constraint)
+}
+
+func (r *InvalidPragmaError) Error() string {
+	return fmt.Sprintf("Invalid version pragma '%s': %s.", r.Pragma, r.Reason)
+}
+
+func (r *LockTimeoutError) Error() string {
+	return fmt.Sprintf("Timed out after %s waiting for '%s' locked by another process.", r.Timeout, r.Path)
+}
+
+func (r *NotCachedError) Error() string {
+	return fmt.Sprintf("No cached list of compilers from '%s'. Run `gsolc-select refresh` without the --offline flag.", r.Url)
+}
+
+func (r *ChecksumChangedError) Error() string {
+	return fmt.Sprintf("%s checksum of version '%s' for %s platform changed from '%s' to '%s' since it was first seen. Run `gsolc-select trust --reset %s` if the change is legitimate.", r.HashFunc, r.Version, r.Platform, r.Known, r.Received, r.Version)
+}
+
+func (r *ModifiedFileError) Error() string {
+	return fmt.Sprintf("%s checksum of '%s' of version '%s' doesn't match the expected one.", r.HashFunc, r.File, r.Version)
+}
+
+func (r *UnverifiableError) Error() string {
+	return fmt.Sprintf("No checksums to verify version '%s' against. Run `gsolc-select verify --repair %s`.", r.Version, r.Version)
+}
+
+func (r *NoManifestError) Error() string {
+	return fmt.Sprintf("Version '%s' has no manifest, it was installed by an older gsolc-select or copied manually.", r.Version)
+}
+
+func (r *SelfTestError) Error() string {
+	return fmt.Sprintf("Self-test of version '%s' failed: %s.", r.Version, r.Reason)
+}
+
+func (r *UnsafeArchiveError) Error() string {
+	return fmt.Sprintf("Refused to extract '%s' from the archive: %s.", r.Name, r.Reason)
+}
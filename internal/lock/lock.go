@@ -0,0 +1,104 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package lock
+
+import (
+	"context"
+	"fmt"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// retryInterval Delay between attempts to take a lock held by another process
+const retryInterval = 100 * time.Millisecond
+
+// Lock Advisory lock of a file shared between processes
+type Lock struct {
+	file *os.File
+}
+
+// Acquire Takes the lock of the file, waits while the lock is held by another process
+//
+// Returns an error if the lock wasn't released within the timeout or the context was cancelled,
+// a negative timeout means waiting indefinitely
+func Acquire(ctx context.Context, path string, timeout time.Duration) (*Lock, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLock(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+
+		if locked {
+			return &Lock{file: file}, nil
+		}
+
+		if timeout >= 0 && time.Now().After(deadline) {
+			file.Close()
+			return nil, &errors.LockTimeoutError{Path: path, Timeout: timeout.String()}
+		}
+
+		select {
+		case <-ctx.Done():
+			file.Close()
+			return nil, ctx.Err()
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// Release Releases the lock
+func (r *Lock) Release() error {
+	defer r.file.Close()
+	return unlock(r.file)
+}
+
+// Version Takes the lock of a specific compiler version with config.LockTimeout
+//
+// Different versions are locked independently, so they can be installed concurrently
+func Version(ctx context.Context, version string) (*Lock, error) {
+	return Acquire(ctx, filepath.Join(config.SolcLocks, fmt.Sprintf("solc-%s.lock", version)), config.LockTimeout)
+}
+
+// Global Takes the lock of the `global-version` file with config.LockTimeout
+func Global(ctx context.Context) (*Lock, error) {
+	return Acquire(ctx, filepath.Join(config.SolcLocks, "global-version.lock"), config.LockTimeout)
+}
+
+// KnownHashes Takes the lock of the database of known checksums with config.LockTimeout
+func KnownHashes(ctx context.Context) (*Lock, error) {
+	return Acquire(ctx, filepath.Join(config.SolcLocks, "known-hashes.lock"), config.LockTimeout)
+}
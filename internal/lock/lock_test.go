@@ -0,0 +1,46 @@
+package lock
+
+import (
+	"context"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAcquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "locks", "solc-0.8.0.lock")
+
+	l, err := Acquire(context.Background(), path, 0)
+	assert.NoError(t, err)
+
+	t.Run("times out while the lock is held", func(t *testing.T) {
+		_, err := Acquire(context.Background(), path, 200*time.Millisecond)
+		assert.Equal(t, &errors.LockTimeoutError{Path: path, Timeout: "200ms"}, err)
+	})
+
+	t.Run("stops waiting when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		_, err := Acquire(ctx, path, -1)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("other files are locked independently", func(t *testing.T) {
+		other, err := Acquire(context.Background(), filepath.Join(filepath.Dir(path), "solc-0.8.1.lock"), 0)
+		assert.NoError(t, err)
+		assert.NoError(t, other.Release())
+	})
+
+	t.Run("waits until the lock is released", func(t *testing.T) {
+		go func() {
+			time.Sleep(200 * time.Millisecond)
+			l.Release()
+		}()
+
+		l, err := Acquire(context.Background(), path, 5*time.Second)
+		assert.NoError(t, err)
+		assert.NoError(t, l.Release())
+	})
+}
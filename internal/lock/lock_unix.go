@@ -0,0 +1,45 @@
+//go:build !windows
+// +build !windows
+
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package lock
+
+import (
+	"os"
+	"syscall"
+)
+
+// tryLock Takes an exclusive flock of the file without waiting, returns false if the file is locked by another process
+func tryLock(file *os.File) (bool, error) {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err == syscall.EWOULDBLOCK {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// unlock Releases the flock of the file
+func unlock(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
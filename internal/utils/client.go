@@ -0,0 +1,282 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package utils
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HttpConfig Headers sent to repositories and their mirrors, read from config.HttpConfigPath
+//
+// Headers are keyed by url prefix, the longest prefix matching a request url wins.
+// Values may reference environment variables as $NAME or ${NAME}, e.g.
+//
+//	{"headers": {"https://solc.example.com/mirror": {"Authorization": "Bearer ${SOLC_MIRROR_TOKEN}"}}}
+type HttpConfig struct {
+	Headers map[string]map[string]string `json:"headers"`
+}
+
+// netrcEntry Credentials of a machine from a netrc file
+type netrcEntry struct {
+	login    string
+	password string
+}
+
+// httpSetup The client and credentials used for all requests
+type httpSetup struct {
+	client  *http.Client
+	headers map[string]map[string]string
+	netrc   map[string]*netrcEntry
+}
+
+var setup *httpSetup
+var setupErr error
+var setupOnce sync.Once
+
+// httpClient Returns the setup used for all requests, it is built from the config on the first call
+func httpClient() (*httpSetup, error) {
+	setupOnce.Do(func() {
+		setup, setupErr = newHttpSetup()
+	})
+
+	return setup, setupErr
+}
+
+// newHttpSetup Builds the client from config.HttpTimeout, config.CaBundle, config.ClientCert
+// and reads headers and credentials from config.HttpConfigPath and config.Netrc
+func newHttpSetup() (*httpSetup, error) {
+	tlsConfig := &tls.Config{}
+	if config.CaBundle != "" {
+		pem, err := os.ReadFile(config.CaBundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle '%s'", config.CaBundle)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.ClientCert != "" {
+		key := config.ClientKey
+		if key == "" {
+			key = config.ClientCert
+		}
+
+		cert, err := tls.LoadX509KeyPair(config.ClientCert, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	httpConfig, err := readHttpConfig(config.HttpConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	netrc, err := readNetrc(config.Netrc)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: config.HttpTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = config.HttpTimeout
+	transport.ResponseHeaderTimeout = config.HttpTimeout
+	transport.TLSClientConfig = tlsConfig
+
+	s := &httpSetup{headers: httpConfig.Headers, netrc: netrc}
+	s.client = &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return fmt.Errorf("stopped after 10 redirects")
+			}
+
+			// Headers of a mirror must not leak to the server it redirects to
+			for _, headers := range s.headers {
+				for name := range headers {
+					req.Header.Del(name)
+				}
+			}
+
+			// The client copies Authorization to redirects on the same host, even to plain http where credentials
+			// would travel in cleartext. It's kept only for https on the same host, authorize sets it again if needed
+			if req.URL.Scheme != "https" || req.URL.Host != via[0].URL.Host {
+				req.Header.Del("Authorization")
+			}
+
+			s.authorize(req)
+			return nil
+		},
+	}
+
+	return s, nil
+}
+
+// authorize Sets the User-Agent, headers of the longest url prefix matching the request
+// and credentials from netrc if the request has no Authorization header
+//
+// Netrc credentials are sent over https only, Basic auth of a plain http request would travel in cleartext
+func (s *httpSetup) authorize(req *http.Request) {
+	req.Header.Set("User-Agent", config.UserAgent)
+
+	var prefix string
+	for p := range s.headers {
+		if len(p) > len(prefix) && matchPrefix(p, req.URL) {
+			prefix = p
+		}
+	}
+
+	for name, value := range s.headers[prefix] {
+		req.Header.Set(name, os.ExpandEnv(value))
+	}
+
+	if req.Header.Get("Authorization") != "" {
+		return
+	}
+
+	if entry := s.netrc[req.URL.Hostname()]; entry != nil && req.URL.Scheme == "https" {
+		req.SetBasicAuth(entry.login, entry.password)
+	}
+}
+
+// matchPrefix Checks if the url starts with the prefix, the scheme and host must be equal
+// and the path must continue at a segment boundary
+func matchPrefix(prefix string, u *url.URL) bool {
+	p, err := url.Parse(strings.TrimSuffix(prefix, "/"))
+	if err != nil || p.Host == "" {
+		return false
+	}
+
+	if !strings.EqualFold(p.Scheme, u.Scheme) || !strings.EqualFold(p.Host, u.Host) {
+		return false
+	}
+
+	return p.Path == "" || u.Path == p.Path || strings.HasPrefix(u.Path, p.Path+"/")
+}
+
+// readHttpConfig Reads the config file, a missing file means an empty config
+func readHttpConfig(path string) (*HttpConfig, error) {
+	httpConfig := &HttpConfig{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return httpConfig, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, httpConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse '%s': %w", path, err)
+	}
+
+	return httpConfig, nil
+}
+
+// readNetrc Reads credentials of machines from a netrc file, a missing file means no credentials
+//
+// The `default` entry is ignored so that credentials are never sent to public repositories
+func readNetrc(path string) (map[string]*netrcEntry, error) {
+	entries := map[string]*netrcEntry{}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entry *netrcEntry
+	var macro bool
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if macro {
+			// A macro definition ends with an empty line
+			macro = strings.TrimSpace(line) != ""
+			continue
+		}
+
+		fields := strings.Fields(line)
+		for i := 0; i < len(fields); i++ {
+			if strings.HasPrefix(fields[i], "#") {
+				break
+			}
+
+			var value string
+			if i+1 < len(fields) {
+				value = fields[i+1]
+			}
+
+			switch fields[i] {
+			case "machine":
+				entry = &netrcEntry{}
+				entries[value] = entry
+				i++
+			case "default":
+				entry = nil
+			case "login":
+				if entry != nil {
+					entry.login = value
+				}
+				i++
+			case "password":
+				if entry != nil {
+					entry.password = value
+				}
+				i++
+			case "account":
+				i++
+			case "macdef":
+				macro = true
+				i = len(fields)
+			}
+		}
+	}
+
+	return entries, scanner.Err()
+}
@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// useHttpConfig Makes the next request build the client from the passed files, the previous config is restored on cleanup
+func useHttpConfig(t *testing.T, httpConfig string, netrc string, caBundle string, clientCert string, clientKey string) {
+	previous := []string{config.HttpConfigPath, config.Netrc, config.CaBundle, config.ClientCert, config.ClientKey}
+	config.HttpConfigPath, config.Netrc, config.CaBundle, config.ClientCert, config.ClientKey = httpConfig, netrc, caBundle, clientCert, clientKey
+	setupOnce = sync.Once{}
+	t.Cleanup(func() {
+		config.HttpConfigPath, config.Netrc, config.CaBundle, config.ClientCert, config.ClientKey = previous[0], previous[1], previous[2], previous[3], previous[4]
+		setupOnce = sync.Once{}
+	})
+}
+
+// writePem Writes the DER bytes to a new PEM file and returns its path
+func writePem(t *testing.T, name string, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600)
+	assert.NoError(t, err)
+	return path
+}
+
+func TestHttpClient(t *testing.T) {
+	retries := config.HttpRetries
+	config.HttpRetries = 0
+	defer func() { config.HttpRetries = retries }()
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		if r.URL.Path == "/mirror/redirect" {
+			http.Redirect(w, r, "/public/list.json", http.StatusFound)
+			return
+		}
+
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	httpConfig := filepath.Join(dir, "http.json")
+	os.WriteFile(httpConfig, []byte(`{"headers": {"`+server.URL+`/mirror": {"Authorization": "Bearer ${TEST_MIRROR_TOKEN}", "X-Mirror": "yes"}}}`), 0600)
+	netrc := filepath.Join(dir, "netrc")
+	os.WriteFile(netrc, []byte("# credentials\nmachine 127.0.0.1 login user password secret\ndefault login anonymous password leaked\n"), 0600)
+	os.Setenv("TEST_MIRROR_TOKEN", "token")
+	defer os.Unsetenv("TEST_MIRROR_TOKEN")
+
+	t.Run("sends user agent", func(t *testing.T) {
+		useHttpConfig(t, filepath.Join(dir, "missing.json"), filepath.Join(dir, "missing"), "", "", "")
+		_, err := Get(server.URL + "/list.json")
+		assert.NoError(t, err)
+		assert.Equal(t, "gsolc-select/"+config.GoSolcSelect, header.Get("User-Agent"))
+		assert.Empty(t, header.Get("Authorization"))
+	})
+
+	t.Run("sends headers of matching mirror", func(t *testing.T) {
+		useHttpConfig(t, httpConfig, filepath.Join(dir, "missing"), "", "", "")
+		_, err := Get(server.URL + "/mirror/list.json")
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer token", header.Get("Authorization"))
+		assert.Equal(t, "yes", header.Get("X-Mirror"))
+
+		_, err = Get(server.URL + "/mirrored/list.json")
+		assert.NoError(t, err)
+		assert.Empty(t, header.Get("Authorization"))
+		assert.Empty(t, header.Get("X-Mirror"))
+	})
+
+	t.Run("drops headers of mirror on redirect", func(t *testing.T) {
+		useHttpConfig(t, httpConfig, filepath.Join(dir, "missing"), "", "", "")
+		_, err := Get(server.URL + "/mirror/redirect")
+		assert.NoError(t, err)
+		assert.Empty(t, header.Get("X-Mirror"))
+		assert.Empty(t, header.Get("Authorization"))
+		assert.Equal(t, "gsolc-select/"+config.GoSolcSelect, header.Get("User-Agent"))
+	})
+
+	t.Run("doesn't send netrc credentials over plain http", func(t *testing.T) {
+		useHttpConfig(t, filepath.Join(dir, "missing.json"), netrc, "", "", "")
+		_, err := Get(server.URL + "/list.json")
+		assert.NoError(t, err)
+		assert.Empty(t, header.Get("Authorization"))
+	})
+
+	t.Run("mirror headers take precedence over netrc", func(t *testing.T) {
+		useHttpConfig(t, httpConfig, netrc, "", "", "")
+		_, err := Get(server.URL + "/mirror/list.json")
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer token", header.Get("Authorization"))
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		invalid := filepath.Join(dir, "invalid.json")
+		os.WriteFile(invalid, []byte("{"), 0600)
+		useHttpConfig(t, invalid, "", "", "", "")
+		_, err := Get(server.URL + "/list.json")
+		assert.Error(t, err)
+	})
+}
+
+func TestHttpClientTls(t *testing.T) {
+	retries := config.HttpRetries
+	config.HttpRetries = 0
+	defer func() { config.HttpRetries = retries }()
+
+	// CA issuing the client certificate
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	ca, _ := x509.ParseCertificate(caDer)
+
+	clientKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDer, err := x509.CreateCertificate(rand.Reader, clientTemplate, ca, &clientKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	clientKeyDer, _ := x509.MarshalECPrivateKey(clientKey)
+	certPath := writePem(t, "client.pem", "CERTIFICATE", clientDer)
+	keyPath := writePem(t, "client.key", "EC PRIVATE KEY", clientKeyDer)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	var header http.Header
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Write([]byte("{}"))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	server.StartTLS()
+	defer server.Close()
+
+	caBundle := writePem(t, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	t.Run("rejects unknown CA", func(t *testing.T) {
+		useHttpConfig(t, missing, missing, "", certPath, keyPath)
+		_, err := Get(server.URL)
+		assert.Error(t, err)
+	})
+
+	t.Run("requires client certificate", func(t *testing.T) {
+		useHttpConfig(t, missing, missing, caBundle, "", "")
+		_, err := Get(server.URL)
+		assert.Error(t, err)
+	})
+
+	t.Run("trusts CA bundle and presents client certificate", func(t *testing.T) {
+		useHttpConfig(t, missing, missing, caBundle, certPath, keyPath)
+		data, err := Get(server.URL)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("{}"), data)
+	})
+
+	t.Run("sends netrc credentials over https", func(t *testing.T) {
+		netrc := filepath.Join(t.TempDir(), "netrc")
+		os.WriteFile(netrc, []byte("machine 127.0.0.1 login user password secret\n"), 0600)
+		useHttpConfig(t, missing, netrc, caBundle, certPath, keyPath)
+		_, err := Get(server.URL)
+		assert.NoError(t, err)
+		req := &http.Request{Header: header}
+		login, password, ok := req.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "user", login)
+		assert.Equal(t, "secret", password)
+	})
+
+	t.Run("doesn't send netrc credentials on redirect to plain http", func(t *testing.T) {
+		var plainHeader http.Header
+		plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			plainHeader = r.Header.Clone()
+			w.Write([]byte("{}"))
+		}))
+		defer plain.Close()
+		redirecting := httptest.NewUnstartedServer(http.RedirectHandler(plain.URL+"/list.json", http.StatusFound))
+		redirecting.TLS = server.TLS
+		redirecting.StartTLS()
+		defer redirecting.Close()
+
+		netrc := filepath.Join(t.TempDir(), "netrc")
+		os.WriteFile(netrc, []byte("machine 127.0.0.1 login user password secret\n"), 0600)
+		useHttpConfig(t, missing, netrc, writePem(t, "redirecting.pem", "CERTIFICATE", redirecting.Certificate().Raw), certPath, keyPath)
+		_, err := Get(redirecting.URL)
+		assert.NoError(t, err)
+		assert.NotNil(t, plainHeader)
+		assert.Empty(t, plainHeader.Get("Authorization"))
+
+		// The client copies Authorization to a redirect on the same host regardless of the scheme
+		s, err := httpClient()
+		assert.NoError(t, err)
+		via, _ := http.NewRequest(http.MethodGet, "https://127.0.0.1/list.json", nil)
+		via.SetBasicAuth("user", "secret")
+		req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1/list.json", nil)
+		req.Header.Set("Authorization", via.Header.Get("Authorization"))
+		assert.NoError(t, s.client.CheckRedirect(req, []*http.Request{via}))
+		assert.Empty(t, req.Header.Get("Authorization"))
+
+		req, _ = http.NewRequest(http.MethodGet, "https://127.0.0.1/other/list.json", nil)
+		req.Header.Set("Authorization", via.Header.Get("Authorization"))
+		assert.NoError(t, s.client.CheckRedirect(req, []*http.Request{via}))
+		assert.Equal(t, via.Header.Get("Authorization"), req.Header.Get("Authorization"))
+	})
+
+	t.Run("invalid CA bundle", func(t *testing.T) {
+		useHttpConfig(t, missing, missing, certPath+"-missing", "", "")
+		_, err := Get(server.URL)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,436 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	goerrors "errors"
+	"fmt"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"golang.org/x/crypto/sha3"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// errStalled The error returned when no data was received within config.HttpTimeout
+var errStalled = goerrors.New("no data received within timeout")
+
+// Checksum Computes Sha256 and Keccak256 sums of the data written to it
+type Checksum struct {
+	sha256    hash.Hash
+	keccak256 hash.Hash
+}
+
+// NewChecksum Returns an empty checksum
+func NewChecksum() *Checksum {
+	return &Checksum{sha256: sha256.New(), keccak256: sha3.NewLegacyKeccak256()}
+}
+
+// Write Adds data to the checksum, never returns an error
+func (c *Checksum) Write(p []byte) (int, error) {
+	c.sha256.Write(p)
+	c.keccak256.Write(p)
+	return len(p), nil
+}
+
+// Sha256 Returns the hex encoded Sha256 sum of the written data in the format of the solc builds list
+func (c *Checksum) Sha256() string {
+	return fmt.Sprintf("0x%x", c.sha256.Sum(nil))
+}
+
+// Keccak256 Returns the hex encoded Keccak256 sum of the written data in the format of the solc builds list
+func (c *Checksum) Keccak256() string {
+	return fmt.Sprintf("0x%x", c.keccak256.Sum(nil))
+}
+
+// Verify Compares the sums of the written data with expected ones, returns an error if a sum is incorrect
+func (c *Checksum) Verify(k256 string, s256 string) error {
+	if c.Sha256() != s256 {
+		return &errors.ChecksumMismatchError{HashFunc: "Sha256", Platform: runtime.GOOS}
+	}
+
+	if c.Keccak256() != k256 {
+		return &errors.ChecksumMismatchError{HashFunc: "Keccak256", Platform: runtime.GOOS}
+	}
+
+	return nil
+}
+
+// HashFile Returns the checksum of the file content
+func HashFile(path string) (*Checksum, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	checksum := NewChecksum()
+	_, err = io.Copy(checksum, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return checksum, nil
+}
+
+// progressWriter Reports the number of written bytes after each write
+type progressWriter struct {
+	writer   io.Writer
+	written  int64
+	total    int64
+	progress func(int64, int64)
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.written += int64(n)
+	w.progress(w.written, w.total)
+	return n, err
+}
+
+// stallReader Cancels a request if reading of the response body blocks longer than the timeout
+type stallReader struct {
+	reader  io.Reader
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func (r *stallReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.timer.Reset(r.timeout)
+	return n, err
+}
+
+// isRetryable Determines whether a failed request is worth retrying
+//
+// Server errors, throttling, timeouts and dropped connections are considered transient
+func isRetryable(err error) bool {
+	var restartErr *restartError
+	if goerrors.As(err, &restartErr) {
+		return true
+	}
+
+	var statusErr *errors.UnexpectedStatusCode
+	if goerrors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	if goerrors.Is(err, context.Canceled) || goerrors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	// An unknown host won't appear with retries
+	var dnsErr *net.DNSError
+	if goerrors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return false
+	}
+
+	// Neither will an untrusted certificate
+	var authorityErr x509.UnknownAuthorityError
+	var certErr x509.CertificateInvalidError
+	var hostnameErr x509.HostnameError
+	if goerrors.As(err, &authorityErr) || goerrors.As(err, &certErr) || goerrors.As(err, &hostnameErr) {
+		return false
+	}
+
+	var netErr net.Error
+	return goerrors.Is(err, errStalled) ||
+		goerrors.Is(err, io.ErrUnexpectedEOF) ||
+		goerrors.Is(err, syscall.ECONNRESET) ||
+		goerrors.Is(err, syscall.ECONNREFUSED) ||
+		goerrors.As(err, &netErr)
+}
+
+// retry Calls the function until it succeeds, fails with a non-retryable error or config.HttpRetries is exhausted
+//
+// The delay between attempts starts at config.HttpRetryBackoff and doubles after each attempt
+func retry(ctx context.Context, fn func() error) error {
+	backoff := config.HttpRetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= config.HttpRetries || ctx.Err() != nil || !isRetryable(err) {
+			var restartErr *restartError
+			if goerrors.As(err, &restartErr) {
+				return restartErr.err
+			}
+
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+	}
+}
+
+// do Sends the request and passes the response to the handler, the response body is read with config.HttpTimeout stall detection
+func do(ctx context.Context, req *http.Request, handle func(*http.Response, io.Reader) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s, err := httpClient()
+	if err != nil {
+		return err
+	}
+
+	s.authorize(req)
+	r, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	timer := time.AfterFunc(config.HttpTimeout, cancel)
+	defer timer.Stop()
+
+	err = handle(r, &stallReader{reader: r.Body, timer: timer, timeout: config.HttpTimeout})
+	if err != nil && ctx.Err() != nil && req.Context().Err() == nil {
+		// The request was cancelled by the timer, not by the caller
+		return errStalled
+	}
+
+	return err
+}
+
+// Response Status, headers and body of a response
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// GetContext Requests the url and returns the response body, transient failures are retried
+func GetContext(ctx context.Context, url string) ([]byte, error) {
+	r, err := Fetch(ctx, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Body, nil
+}
+
+// Fetch Requests the url with the passed headers, transient failures are retried
+//
+// Returns the response with status 200, or 304 if the request was conditional and the resource wasn't modified
+func Fetch(ctx context.Context, url string, header http.Header) (*Response, error) {
+	var response *Response
+	err := retry(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		for key, values := range header {
+			req.Header[key] = values
+		}
+
+		return do(ctx, req, func(r *http.Response, body io.Reader) error {
+			if r.StatusCode != http.StatusOK && r.StatusCode != http.StatusNotModified {
+				return &errors.UnexpectedStatusCode{StatusCode: r.StatusCode, Url: url}
+			}
+
+			data, err := ioutil.ReadAll(body)
+			if err != nil {
+				return err
+			}
+
+			response = &Response{StatusCode: r.StatusCode, Header: r.Header, Body: data}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Download Streams the file from the url to the path, returns the size of the file
+//
+// Data is written to `<path>.part` and hashed on the fly, the file is moved to the path only if its checksums match.
+// An interrupted download is resumed from the partial file with a range request, transient failures are retried.
+// If the partial file turns out not to belong to the remote file, the download starts over once without using up a retry.
+// The progress function, if passed, receives the number of bytes in the partial file and the total size (-1 if unknown)
+func Download(ctx context.Context, url string, path string, k256 string, s256 string, progress func(int64, int64)) (int64, error) {
+	part := path + ".part"
+	var size int64
+	err := retry(ctx, func() error {
+		var err error
+		size, err = downloadPart(ctx, url, part, k256, s256, progress)
+		var restartErr *restartError
+		if goerrors.As(err, &restartErr) {
+			size, err = downloadPart(ctx, url, part, k256, s256, progress)
+		}
+
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	err = os.Rename(part, path)
+	if err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
+// downloadPart Makes a single attempt to complete the partial file and verify it, returns the size of the file
+func downloadPart(ctx context.Context, url string, part string, k256 string, s256 string, progress func(int64, int64)) (int64, error) {
+	file, err := os.OpenFile(part, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	checksum := NewChecksum()
+	var size int64
+	err = do(ctx, req, func(r *http.Response, body io.Reader) error {
+		switch {
+		case r.StatusCode == http.StatusPartialContent && offset > 0:
+			// Hash the data downloaded by previous attempts
+			_, err := io.Copy(checksum, io.NewSectionReader(file, 0, offset))
+			if err != nil {
+				return err
+			}
+		case r.StatusCode == http.StatusOK:
+			// The server ignored the range, start over
+			offset = 0
+			err := file.Truncate(0)
+			if err != nil {
+				return err
+			}
+
+			_, err = file.Seek(0, io.SeekStart)
+			if err != nil {
+				return err
+			}
+		case r.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0 && remoteSize(r) == offset:
+			// The partial file is already complete, it's only verified
+			_, err := io.Copy(checksum, io.NewSectionReader(file, 0, offset))
+			if progress != nil {
+				progress(offset, offset)
+			}
+
+			size = offset
+			return err
+		case r.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+			// The partial file is not a prefix of the remote file, start over
+			file.Truncate(0)
+			return &errors.UnexpectedStatusCode{StatusCode: r.StatusCode, Url: url}
+		default:
+			return &errors.UnexpectedStatusCode{StatusCode: r.StatusCode, Url: url}
+		}
+
+		var writer io.Writer = io.MultiWriter(file, checksum)
+		if progress != nil {
+			total := int64(-1)
+			if r.ContentLength >= 0 {
+				total = offset + r.ContentLength
+			}
+
+			progress(offset, total)
+			writer = &progressWriter{writer: writer, written: offset, total: total, progress: progress}
+		}
+
+		n, err := io.Copy(writer, body)
+		size = offset + n
+		return err
+	})
+	if err != nil {
+		var statusErr *errors.UnexpectedStatusCode
+		if goerrors.As(err, &statusErr) && statusErr.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			return 0, &restartError{err}
+		}
+
+		return 0, err
+	}
+
+	err = checksum.Verify(k256, s256)
+	if err != nil {
+		file.Close()
+		os.Remove(part)
+		if offset > 0 {
+			// The resumed data may not belong to the same file, start over
+			return 0, &restartError{err}
+		}
+
+		return 0, err
+	}
+
+	return size, nil
+}
+
+// remoteSize Returns the size of the remote file reported by a response to an unsatisfiable range request, -1 if unknown
+func remoteSize(r *http.Response) int64 {
+	var size int64
+	_, err := fmt.Sscanf(r.Header.Get("Content-Range"), "bytes */%d", &size)
+	if err != nil {
+		return -1
+	}
+
+	return size
+}
+
+// restartError Marks an error after which the partial file is discarded and the download must start over
+type restartError struct {
+	err error
+}
+
+func (e *restartError) Error() string {
+	return e.err.Error()
+}
+
+func (e *restartError) Unwrap() error {
+	return e.err
+}
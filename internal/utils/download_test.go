@@ -0,0 +1,241 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/sha3"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// checksums Returns keccak256 and sha256 sums of the data in the format of the solc builds list
+func checksums(data []byte) (string, string) {
+	k := sha3.NewLegacyKeccak256()
+	k.Write(data)
+	return fmt.Sprintf("0x%x", k.Sum(nil)), fmt.Sprintf("0x%x", sha256.Sum256(data))
+}
+
+func TestDownload(t *testing.T) {
+	config.HttpRetryBackoff = time.Millisecond
+	data := bytes.Repeat([]byte("supernatural"), 1024)
+	k256, s256 := checksums(data)
+
+	t.Run("downloads and verifies the file", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(data)
+		}))
+		defer server.Close()
+
+		path := filepath.Join(t.TempDir(), "solc")
+		size, err := Download(context.Background(), server.URL, path, k256, s256, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(data)), size)
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, data, content)
+		assert.NoFileExists(t, path+".part")
+	})
+
+	t.Run("retries server errors", func(t *testing.T) {
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&requests, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			w.Write(data)
+		}))
+		defer server.Close()
+
+		path := filepath.Join(t.TempDir(), "solc")
+		_, err := Download(context.Background(), server.URL, path, k256, s256, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), requests)
+	})
+
+	t.Run("gives up after retries", func(t *testing.T) {
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		path := filepath.Join(t.TempDir(), "solc")
+		_, err := Download(context.Background(), server.URL, path, k256, s256, nil)
+		assert.EqualError(t, err, fmt.Sprintf("Recieved unexpected status code: '%d' from '%s' request.", http.StatusBadGateway, server.URL))
+		assert.Equal(t, int32(config.HttpRetries+1), requests)
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		path := filepath.Join(t.TempDir(), "solc")
+		_, err := Download(context.Background(), server.URL, path, k256, s256, nil)
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), requests)
+	})
+
+	t.Run("reports progress", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.ServeContent(w, r, "solc", time.Time{}, bytes.NewReader(data))
+		}))
+		defer server.Close()
+
+		path := filepath.Join(t.TempDir(), "solc")
+		os.WriteFile(path+".part", data[:100], 0644)
+		var reports [][2]int64
+		_, err := Download(context.Background(), server.URL, path, k256, s256, func(written int64, total int64) {
+			reports = append(reports, [2]int64{written, total})
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, [2]int64{100, int64(len(data))}, reports[0])
+		assert.Equal(t, [2]int64{int64(len(data)), int64(len(data))}, reports[len(reports)-1])
+	})
+
+	t.Run("resumes a partial file", func(t *testing.T) {
+		var ranges []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ranges = append(ranges, r.Header.Get("Range"))
+			http.ServeContent(w, r, "solc", time.Time{}, bytes.NewReader(data))
+		}))
+		defer server.Close()
+
+		path := filepath.Join(t.TempDir(), "solc")
+		os.WriteFile(path+".part", data[:100], 0644)
+		size, err := Download(context.Background(), server.URL, path, k256, s256, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(data)), size)
+		assert.Equal(t, []string{"bytes=100-"}, ranges)
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, data, content)
+	})
+
+	t.Run("resumes a stalled download", func(t *testing.T) {
+		timeout := config.HttpTimeout
+		config.HttpTimeout = 100 * time.Millisecond
+		defer func() { config.HttpTimeout = timeout }()
+
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&requests, 1) == 1 {
+				w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+				w.Write(data[:100])
+				w.(http.Flusher).Flush()
+				<-r.Context().Done()
+				return
+			}
+
+			http.ServeContent(w, r, "solc", time.Time{}, bytes.NewReader(data))
+		}))
+		defer server.Close()
+
+		path := filepath.Join(t.TempDir(), "solc")
+		_, err := Download(context.Background(), server.URL, path, k256, s256, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), requests)
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, data, content)
+	})
+
+	t.Run("starts over when the partial file is corrupted", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.ServeContent(w, r, "solc", time.Time{}, bytes.NewReader(data))
+		}))
+		defer server.Close()
+
+		path := filepath.Join(t.TempDir(), "solc")
+		os.WriteFile(path+".part", []byte("corrupted"), 0644)
+		_, err := Download(context.Background(), server.URL, path, k256, s256, nil)
+		assert.NoError(t, err)
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, data, content)
+	})
+
+	t.Run("verifies a complete partial file", func(t *testing.T) {
+		retries := config.HttpRetries
+		config.HttpRetries = 0
+		defer func() { config.HttpRetries = retries }()
+
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			http.ServeContent(w, r, "solc", time.Time{}, bytes.NewReader(data))
+		}))
+		defer server.Close()
+
+		path := filepath.Join(t.TempDir(), "solc")
+		os.WriteFile(path+".part", data, 0644)
+		size, err := Download(context.Background(), server.URL, path, k256, s256, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(data)), size)
+		assert.Equal(t, int32(1), requests)
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, data, content)
+	})
+
+	t.Run("starts over without retries", func(t *testing.T) {
+		retries := config.HttpRetries
+		config.HttpRetries = 0
+		defer func() { config.HttpRetries = retries }()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.ServeContent(w, r, "solc", time.Time{}, bytes.NewReader(data))
+		}))
+		defer server.Close()
+
+		parts := [][]byte{
+			// The remote file is a prefix of the partial file
+			append(append([]byte{}, data...), "trailing"...),
+			[]byte("corrupted"),
+		}
+		for _, part := range parts {
+			path := filepath.Join(t.TempDir(), "solc")
+			os.WriteFile(path+".part", part, 0644)
+			_, err := Download(context.Background(), server.URL, path, k256, s256, nil)
+			assert.NoError(t, err)
+			content, _ := os.ReadFile(path)
+			assert.Equal(t, data, content)
+		}
+	})
+
+	t.Run("fails on checksum mismatch", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("corrupted"))
+		}))
+		defer server.Close()
+
+		path := filepath.Join(t.TempDir(), "solc")
+		_, err := Download(context.Background(), server.URL, path, k256, s256, nil)
+		assert.Error(t, err)
+		assert.NoFileExists(t, path)
+		assert.NoFileExists(t, path+".part")
+	})
+
+	t.Run("honors the context", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		path := filepath.Join(t.TempDir(), "solc")
+		_, err := Download(ctx, server.URL, path, k256, s256, nil)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
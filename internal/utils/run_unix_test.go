@@ -0,0 +1,62 @@
+//go:build !windows
+// +build !windows
+
+package utils
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRunSignals(t *testing.T) {
+	// The script reports the signal it received by its exit code
+	script := `trap "exit 2" INT; trap "exit 15" TERM; echo ready; sleep 1 & wait; exit 0`
+	testCases := []struct {
+		name     string
+		signal   syscall.Signal
+		expected int
+	}{
+		{name: "forwards termination signal", signal: syscall.SIGTERM, expected: 15},
+		{name: "forwards interrupt sent to the caller alone", signal: syscall.SIGINT, expected: 2},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cmd := exec.Command("sh", "-c", script)
+			stdout, err := cmd.StdoutPipe()
+			assert.NoError(t, err)
+			go func() {
+				// Signals the test process alone once the traps are set
+				stdout.Read(make([]byte, 8))
+				time.Sleep(100 * time.Millisecond)
+				syscall.Kill(syscall.Getpid(), testCase.signal)
+			}()
+
+			code, err := Run(cmd)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, code)
+		})
+	}
+}
+
+func TestRunSignaledExitCode(t *testing.T) {
+	t.Run("command killed by a signal", func(t *testing.T) {
+		code, err := Run(exec.Command("sh", "-c", "kill -TERM $$"))
+		assert.NoError(t, err)
+		assert.Equal(t, 128+int(syscall.SIGTERM), code)
+	})
+
+	t.Run("command killed by a forwarded signal", func(t *testing.T) {
+		cmd := exec.Command("sleep", "5")
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+		}()
+
+		code, err := Run(cmd)
+		assert.NoError(t, err)
+		assert.Equal(t, 128+int(syscall.SIGINT), code)
+	})
+}
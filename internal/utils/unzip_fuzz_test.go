@@ -0,0 +1,72 @@
+//go:build go1.18
+// +build go1.18
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func FuzzUnzip(f *testing.F) {
+	f.Add(makeZip(f, zipEntry{name: "solc.exe", content: "solc"}, zipEntry{name: "lib/z3.dll", content: "z3"}))
+	f.Add(makeZip(f, zipEntry{name: "../evil", content: "evil"}))
+	f.Add(makeZip(f, zipEntry{name: "lib/../../evil", content: "evil"}))
+	f.Add(makeZip(f, zipEntry{name: "..\\evil", content: "evil"}))
+	f.Add(makeZip(f, zipEntry{name: "/evil", content: "evil"}))
+	f.Add(makeZip(f, zipEntry{name: "C:\\evil", content: "evil"}))
+	f.Add(makeZip(f, zipEntry{name: "lib", content: "..", mode: os.ModeSymlink | 0777}, zipEntry{name: "lib/evil", content: "evil"}))
+	f.Add(makeZip(f, zipEntry{name: "solc.exe", content: strings.Repeat("a", 1<<12)}))
+	f.Add(makeLyingZip(f, "solc.exe", strings.Repeat("a", 1<<12), 4))
+
+	fileSize, totalSize := config.UnzipMaxFileSize, config.UnzipMaxTotalSize
+	config.UnzipMaxFileSize, config.UnzipMaxTotalSize = 1<<10, 1<<11
+	defer func() { config.UnzipMaxFileSize, config.UnzipMaxTotalSize = fileSize, totalSize }()
+
+	f.Fuzz(func(t *testing.T, archive []byte) {
+		parent := t.TempDir()
+		folder := filepath.Join(parent, "solc")
+		os.Mkdir(folder, 0755)
+
+		// Malicious archives must fail, but never write outside of the folder or beyond the limits
+		Unzip(context.Background(), folder, bytes.NewReader(archive), int64(len(archive)))
+
+		entries, _ := os.ReadDir(parent)
+		if len(entries) != 1 {
+			t.Fatalf("files extracted outside of the folder: %v", entries)
+		}
+
+		var total int64
+		filepath.WalkDir(folder, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !entry.IsDir() && !entry.Type().IsRegular() {
+				t.Fatalf("special file extracted: %s", path)
+			}
+
+			info, err := entry.Info()
+			if err == nil && entry.Type().IsRegular() {
+				if info.Size() > config.UnzipMaxFileSize {
+					t.Fatalf("file exceeding the size limit extracted: %s", path)
+				}
+
+				total += info.Size()
+			}
+
+			return nil
+		})
+
+		// The last file may be cut off at the limit of the remaining size
+		if total > config.UnzipMaxTotalSize+1 {
+			t.Fatalf("files exceeding the total size limit extracted: %d bytes", total)
+		}
+	})
+}
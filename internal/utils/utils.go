@@ -23,19 +23,20 @@ package utils
 
 import (
 	"archive/zip"
-	"bytes"
-	"crypto/sha256"
+	"context"
 	"fmt"
 	"github.com/Masterminds/semver"
 	"github.com/fabelx/go-solc-select/internal/errors"
 	"github.com/fabelx/go-solc-select/pkg/config"
-	"golang.org/x/crypto/sha3"
 	"io"
-	"io/ioutil"
-	"net/http"
 	"os"
+	"os/exec"
+	"os/signal"
+	"path"
 	"path/filepath"
-	"runtime"
+	"strings"
+	"syscall"
+	"time"
 )
 
 type ResponseData struct {
@@ -44,31 +45,19 @@ type ResponseData struct {
 }
 
 type BuildData struct {
-	Path      string `json:"path"`
-	Name      string `json:"name"`
-	Version   string `json:"version"`
-	Keccak256 string `json:"keccak256"`
-	Sha256    string `json:"sha256"`
+	Path        string   `json:"path"`
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Build       string   `json:"build,omitempty"`
+	LongVersion string   `json:"longVersion,omitempty"`
+	Keccak256   string   `json:"keccak256"`
+	Sha256      string   `json:"sha256"`
+	Urls        []string `json:"urls,omitempty"`
 }
 
 // Get Base implementation of request
 func Get(url string) ([]byte, error) {
-	r, err := http.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer r.Body.Close()
-
-	if r.StatusCode == http.StatusOK {
-		data, err := ioutil.ReadAll(r.Body)
-		if err != nil {
-			return nil, err
-		}
-
-		return data, nil
-	}
-
-	return nil, &errors.UnexpectedStatusCode{StatusCode: r.StatusCode, Url: url}
+	return GetContext(context.Background(), url)
 }
 
 // IsOldLinuxVersion Determines if the compiler version for Linux is old
@@ -90,74 +79,180 @@ func IsOldWindowsVersion(version string) bool {
 }
 
 // Unzip Decompresses a file to a specific folder, returns an error on failure during decompression
-func Unzip(folder string, data []byte) error {
-	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+// or if the context was cancelled
+//
+// Only regular files and directories inside the folder are extracted, entries with absolute paths, paths escaping
+// the folder, symlinks and other special files are refused. Decompressed sizes are limited by
+// config.UnzipMaxFileSize and config.UnzipMaxTotalSize, so a malicious archive can't exhaust the disk
+func Unzip(ctx context.Context, folder string, r io.ReaderAt, size int64) error {
+	zipReader, err := zip.NewReader(r, size)
 	if err != nil {
 		return err
 	}
 
-	for _, archiveReader := range zipReader.File {
+	var total int64
+	for _, entry := range zipReader.File {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 
-		// Open the file in the archive
-		archiveFile, err := archiveReader.Open()
+		finalPath, err := entryPath(folder, entry.Name)
 		if err != nil {
 			return err
 		}
-		defer archiveFile.Close()
-
-		// Prepare to write the file
-		finalPath := filepath.Join(folder, archiveReader.Name)
 
-		// Check if the file to extract is just a directory
-		if archiveReader.FileInfo().IsDir() {
+		mode := entry.Mode()
+		if mode.IsDir() {
 			err = os.MkdirAll(finalPath, 0755)
 			if err != nil {
 				return err
 			}
-			// Continue to the next file in the archive
+
 			continue
 		}
 
-		// Create all needed directories
-		if os.MkdirAll(filepath.Dir(finalPath), 0755) != nil {
-			return err
+		if !mode.IsRegular() {
+			return &errors.UnsafeArchiveError{Name: entry.Name, Reason: fmt.Sprintf("unsupported file type %s", mode.Type())}
 		}
 
-		// Prepare to write the destination file
-		destinationFile, err := os.OpenFile(finalPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, archiveReader.Mode())
+		// Create all needed directories
+		err = os.MkdirAll(filepath.Dir(finalPath), 0755)
 		if err != nil {
 			return err
 		}
-		defer destinationFile.Close()
 
-		// Write the destination file
-		if _, err = io.Copy(destinationFile, archiveFile); err != nil {
+		limit := config.UnzipMaxTotalSize - total
+		if limit > config.UnzipMaxFileSize {
+			limit = config.UnzipMaxFileSize
+		}
+
+		written, err := extract(entry, finalPath, limit)
+		if err != nil {
 			return err
 		}
+
+		total += written
 	}
 
 	return nil
 }
 
+// entryPath Returns the path the archive entry is extracted to, an error if the entry would be placed outside of the folder
+func entryPath(folder string, name string) (string, error) {
+	// Archives created on windows may separate directories with backslashes
+	slashed := strings.ReplaceAll(name, "\\", "/")
+	cleaned := path.Clean(slashed)
+	switch {
+	case path.IsAbs(slashed) || filepath.IsAbs(slashed) || filepath.VolumeName(filepath.FromSlash(slashed)) != "":
+		return "", &errors.UnsafeArchiveError{Name: name, Reason: "absolute path"}
+	case cleaned == ".." || strings.HasPrefix(cleaned, "../"):
+		return "", &errors.UnsafeArchiveError{Name: name, Reason: "path outside of the destination folder"}
+	case cleaned == ".":
+		return "", &errors.UnsafeArchiveError{Name: name, Reason: "empty path"}
+	}
+
+	return filepath.Join(folder, filepath.FromSlash(cleaned)), nil
+}
+
+// extract Writes the content of the archive entry to the destination, returns the number of written bytes
+// and an error if the decompressed content exceeds the limit
+func extract(entry *zip.File, destination string, limit int64) (int64, error) {
+	if entry.UncompressedSize64 > uint64(limit) {
+		return 0, &errors.UnsafeArchiveError{Name: entry.Name, Reason: "decompressed size exceeds the limit"}
+	}
+
+	archiveFile, err := entry.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer archiveFile.Close()
+
+	destinationFile, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return 0, err
+	}
+
+	// The content is limited while decompressing regardless of the declared size
+	written, err := io.Copy(destinationFile, io.LimitReader(archiveFile, limit+1))
+	if closeErr := destinationFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil && written > limit {
+		err = &errors.UnsafeArchiveError{Name: entry.Name, Reason: "decompressed size exceeds the limit"}
+	}
+
+	return written, err
+}
+
 // VerifyChecksum Checks the checksum of the received file, returns an error if the sum is incorrect
 func VerifyChecksum(k256 string, s256 string, data []byte) error {
-	if fmt.Sprintf("0x%x", sha256.Sum256(data)) != s256 {
-		return &errors.ChecksumMismatchError{HashFunc: "Sha256", Platform: runtime.GOOS}
+	checksum := NewChecksum()
+	checksum.Write(data)
+	return checksum.Verify(k256, s256)
+}
+
+// interruptGrace Time the command is given to exit on an interrupt it may have received from the terminal
+// before the interrupt is relayed to it
+const interruptGrace = 100 * time.Millisecond
+
+// Run Runs the command and waits for it to complete, returns the exit code of the command
+//
+// Interrupt and termination signals received while the command is running are caught so that the caller outlives
+// the command and returns its exit code, and are forwarded to the command. An interrupt from the terminal (Ctrl-C)
+// is delivered to the whole foreground process group, so it is relayed only if the command is still running
+// after interruptGrace, an interrupt sent to the caller alone still reaches the command.
+// A command terminated by a signal results in the 128 + signal number exit code, as in shells
+func Run(cmd *exec.Cmd) (int, error) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	err := cmd.Start()
+	if err != nil {
+		return -1, err
 	}
 
-	k := sha3.NewLegacyKeccak256()
-	k.Write(data)
-	if fmt.Sprintf("0x%x", k.Sum(nil)) != k256 {
-		return &errors.ChecksumMismatchError{HashFunc: "Keccak256", Platform: runtime.GOOS}
+	done := make(chan bool)
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case s := <-signals:
+				if s == os.Interrupt {
+					timer := time.NewTimer(interruptGrace)
+					select {
+					case <-timer.C:
+					case <-done:
+						timer.Stop()
+						return
+					}
+				}
+
+				cmd.Process.Signal(s)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err = cmd.Wait()
+	if err == nil {
+		return 0, nil
 	}
 
-	return nil
-}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		// The exit code is -1 if the command was terminated by a signal
+		if code := exitErr.ExitCode(); code >= 0 {
+			return code, nil
+		}
 
-// Clean Removes passed versions
-func Clean(versions []string) {
-	for _, version := range versions {
-		folder := filepath.Join(config.SolcArtifacts, fmt.Sprintf("solc-%s", version))
-		os.RemoveAll(folder)
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return 128 + int(status.Signal()), nil
+		}
+
+		return 1, nil
 	}
+
+	return -1, err
 }
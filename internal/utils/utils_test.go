@@ -1,11 +1,22 @@
 package utils
 
 import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"context"
 	"fmt"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/pkg/config"
 	"github.com/stretchr/testify/assert"
+	"hash/crc32"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -92,10 +103,202 @@ func TestGet(t *testing.T) {
 	}
 }
 
-func TestUnzip(t *testing.T) {
-	// todo: write zip tests
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name:     "command exits successfully",
+			code:     "0",
+			expected: 0,
+		},
+		{
+			name:     "command exits with error",
+			code:     "3",
+			expected: 3,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=TestHelperProcess")
+			cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS="+testCase.code)
+			code, err := Run(cmd)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, code)
+		})
+	}
+
+	t.Run("command does not exist", func(t *testing.T) {
+		_, err := Run(exec.Command("command-does-not-exist"))
+		assert.Error(t, err)
+	})
 }
 
-func TestClean(t *testing.T) {
-	// todo: write clean up tests
+// TestHelperProcess Isn't a real test, it's used as a command run by TestRun
+func TestHelperProcess(t *testing.T) {
+	code := os.Getenv("GO_WANT_HELPER_PROCESS")
+	if code == "" {
+		return
+	}
+
+	if code != "0" {
+		os.Exit(3)
+	}
+
+	os.Exit(0)
+}
+
+// zipEntry File of an archive created by makeZip, the content of a symlink is its target
+type zipEntry struct {
+	name    string
+	content string
+	mode    os.FileMode
+}
+
+// makeZip Returns an archive of the entries
+func makeZip(tb testing.TB, entries ...zipEntry) []byte {
+	buf := &bytes.Buffer{}
+	writer := zip.NewWriter(buf)
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.name, Method: zip.Deflate}
+		if entry.mode != 0 {
+			header.SetMode(entry.mode)
+		}
+
+		file, err := writer.CreateHeader(header)
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		file.Write([]byte(entry.content))
+	}
+
+	if err := writer.Close(); err != nil {
+		tb.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// makeLyingZip Returns an archive of a single file declaring a smaller decompressed size than its content has
+func makeLyingZip(tb testing.TB, name string, content string, declared uint64) []byte {
+	compressed := &bytes.Buffer{}
+	compressor, _ := flate.NewWriter(compressed, flate.DefaultCompression)
+	compressor.Write([]byte(content))
+	compressor.Close()
+
+	buf := &bytes.Buffer{}
+	writer := zip.NewWriter(buf)
+	file, err := writer.CreateRaw(&zip.FileHeader{
+		Name:               name,
+		Method:             zip.Deflate,
+		CRC32:              crc32.ChecksumIEEE([]byte(content)),
+		CompressedSize64:   uint64(compressed.Len()),
+		UncompressedSize64: declared,
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	file.Write(compressed.Bytes())
+	if err = writer.Close(); err != nil {
+		tb.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestUnzip(t *testing.T) {
+	fileSize, totalSize := config.UnzipMaxFileSize, config.UnzipMaxTotalSize
+	config.UnzipMaxFileSize, config.UnzipMaxTotalSize = 16, 24
+	defer func() { config.UnzipMaxFileSize, config.UnzipMaxTotalSize = fileSize, totalSize }()
+
+	testCases := []struct {
+		name    string
+		archive []byte
+		files   map[string]string
+		err     error
+	}{
+		{
+			name: "test files and directories are extracted",
+			archive: makeZip(t,
+				zipEntry{name: "bin/"},
+				zipEntry{name: "solc.exe", content: "solc"},
+				zipEntry{name: "lib/msvcp140.dll", content: "dll"},
+				zipEntry{name: "bin\\z3.dll", content: "z3"},
+			),
+			files: map[string]string{"solc.exe": "solc", "lib/msvcp140.dll": "dll", "bin/z3.dll": "z3"},
+		},
+		{
+			name:    "test path outside of the folder",
+			archive: makeZip(t, zipEntry{name: "../evil", content: "evil"}),
+			err:     &errors.UnsafeArchiveError{Name: "../evil", Reason: "path outside of the destination folder"},
+		},
+		{
+			name:    "test nested path outside of the folder",
+			archive: makeZip(t, zipEntry{name: "lib/../../evil", content: "evil"}),
+			err:     &errors.UnsafeArchiveError{Name: "lib/../../evil", Reason: "path outside of the destination folder"},
+		},
+		{
+			name:    "test path outside of the folder with backslashes",
+			archive: makeZip(t, zipEntry{name: "..\\evil", content: "evil"}),
+			err:     &errors.UnsafeArchiveError{Name: "..\\evil", Reason: "path outside of the destination folder"},
+		},
+		{
+			name:    "test absolute path",
+			archive: makeZip(t, zipEntry{name: "/evil", content: "evil"}),
+			err:     &errors.UnsafeArchiveError{Name: "/evil", Reason: "absolute path"},
+		},
+		{
+			name:    "test symlink",
+			archive: makeZip(t, zipEntry{name: "solc.exe", content: "../evil", mode: os.ModeSymlink | 0777}),
+			err:     &errors.UnsafeArchiveError{Name: "solc.exe", Reason: "unsupported file type L---------"},
+		},
+		{
+			name:    "test file exceeding the size limit",
+			archive: makeZip(t, zipEntry{name: "solc.exe", content: strings.Repeat("a", 17)}),
+			err:     &errors.UnsafeArchiveError{Name: "solc.exe", Reason: "decompressed size exceeds the limit"},
+		},
+		{
+			name: "test files exceeding the total size limit",
+			archive: makeZip(t,
+				zipEntry{name: "solc.exe", content: strings.Repeat("a", 16)},
+				zipEntry{name: "z3.dll", content: strings.Repeat("a", 16)},
+			),
+			err: &errors.UnsafeArchiveError{Name: "z3.dll", Reason: "decompressed size exceeds the limit"},
+		},
+		{
+			name:    "test file declaring a smaller size than it has",
+			archive: makeLyingZip(t, "solc.exe", strings.Repeat("a", 1024), 4),
+			err:     zip.ErrFormat,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			parent := t.TempDir()
+			folder := filepath.Join(parent, "solc")
+			os.Mkdir(folder, 0755)
+
+			err := Unzip(context.Background(), folder, bytes.NewReader(testCase.archive), int64(len(testCase.archive)))
+			assert.Equal(t, testCase.err, err)
+			assert.NoFileExists(t, filepath.Join(parent, "evil"))
+			for name, content := range testCase.files {
+				data, err := os.ReadFile(filepath.Join(folder, filepath.FromSlash(name)))
+				assert.NoError(t, err)
+				assert.Equal(t, content, string(data))
+			}
+		})
+	}
+
+	t.Run("test cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		archive := makeZip(t, zipEntry{name: "solc.exe", content: "solc"})
+		err := Unzip(ctx, t.TempDir(), bytes.NewReader(archive), int64(len(archive)))
+		assert.Equal(t, context.Canceled, err)
+	})
 }
@@ -60,6 +60,12 @@ func getCurrentVersions(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if os.Getenv(config.SolcVersionEnv) != "" {
+		log.Infof("Set by %s environment variable", config.SolcVersionEnv)
+	} else if path, err := ver.GetCurrentVersionFilePath(); err == nil {
+		log.Infof("Set by %s", path)
+	}
+
 	log.Warn(currentVersion)
 	return nil
 }
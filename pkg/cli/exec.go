@@ -0,0 +1,153 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/internal/utils"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	ver "github.com/fabelx/go-solc-select/pkg/versions"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec <version|constraint> -- <command> [args...]",
+	Short: "Run a command with a specific solc version",
+	Long: `gsolc-select
+
+Runs a command with a specific installed version of solc compiler without changing the global version.
+The version is either exact or a semver constraint, the highest installed version satisfying the constraint is used.
+The command finds the selected compiler as 'solc' in PATH, the SOLC_VERSION and SOLC_PATH environment variables
+are set to the selected version and the path to its binary. The exit code of the command is propagated.
+`,
+	Example: `  gsolc-select exec 0.8.19 -- slither .
+  gsolc-select exec "^0.7.0" -- solc --version
+`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: execWithCompiler,
+}
+
+func execWithCompiler(cmd *cobra.Command, args []string) error {
+	constraint := args[0]
+	command := args[1:]
+	if command[0] == "--" {
+		command = command[1:]
+	}
+
+	if len(command) == 0 {
+		return fmt.Errorf("wrong number of args, required a command to run")
+	}
+
+	installedVersions := ver.GetInstalled()
+	if config.ValidSemVer.MatchString(constraint) && installedVersions[constraint] == "" {
+		return &errors.NotInstalledError{Version: constraint}
+	}
+
+	version, err := ver.Resolve(constraint, installedVersions)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("solc-%s", version)
+	solcPath := filepath.Join(config.SolcArtifacts, name, name)
+
+	// Creates a temporary directory containing `solc` linked to the selected compiler
+	binDir, err := os.MkdirTemp("", "gsolc-select-")
+	if err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(binDir)
+
+	linkName := "solc"
+	if runtime.GOOS == "windows" {
+		linkName = "solc.exe"
+	}
+
+	err = linkFile(solcPath, filepath.Join(binDir, linkName))
+	if err != nil {
+		return err
+	}
+
+	// The command is looked up using the updated PATH
+	path := binDir + string(os.PathListSeparator) + os.Getenv("PATH")
+	err = os.Setenv("PATH", path)
+	if err != nil {
+		return err
+	}
+
+	env := os.Environ()
+	env = setEnv(env, config.SolcVersionEnv, version)
+	env = setEnv(env, "SOLC_PATH", solcPath)
+
+	log.Infof("Running %s with solc %s", command[0], version)
+	child := exec.Command(command[0], command[1:]...)
+	child.Env = env
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+	code, err := utils.Run(child)
+	if err != nil {
+		return err
+	}
+
+	if code != 0 {
+		return &exitError{code: code}
+	}
+
+	return nil
+}
+
+// linkFile Creates a link to the file, falls back to a hard link if symbolic links are not available
+func linkFile(oldName string, newName string) error {
+	err := os.Symlink(oldName, newName)
+	if err == nil {
+		return nil
+	}
+
+	return os.Link(oldName, newName)
+}
+
+// setEnv Returns the environment with the variable set to the value
+func setEnv(env []string, key string, value string) []string {
+	prefix := key + "="
+	result := make([]string, 0, len(env)+1)
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, prefix) {
+			result = append(result, kv)
+		}
+	}
+
+	return append(result, prefix+value)
+}
+
+func init() {
+	execCmd.Flags().SetInterspersed(false)
+	RegisterCmd(rootCmd, execCmd)
+}
@@ -0,0 +1,106 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"github.com/fabelx/go-solc-select/pkg/manifest"
+	ver "github.com/fabelx/go-solc-select/pkg/versions"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var infoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show where an installed solc version came from",
+	Long: `gsolc-select
+
+Prints out the manifest recorded at the installation of the solc version: the build from the list of available
+versions with its long version and checksums, the url it was downloaded from, the platform, the time of the installation
+and the gsolc-select version which installed it. Use flag '--json' to print out the manifest as is.
+`,
+	Example: `  gsolc-select info 0.8.1
+  gsolc-select info 0.8.1 --json
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: printInfo,
+}
+
+func printInfo(cmd *cobra.Command, args []string) error {
+	version := args[0]
+	if ver.GetInstalled()[version] == "" {
+		return &errors.NotInstalledError{Version: version}
+	}
+
+	m, err := manifest.Read(filepath.Join(config.SolcArtifacts, fmt.Sprintf("solc-%s", version)))
+	if os.IsNotExist(err) {
+		return &errors.NoManifestError{Version: version}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if jsonFormat {
+		data, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+
+	log.Warnf("Version: %s", m.Version)
+	if m.Build != nil {
+		if m.Build.LongVersion != "" {
+			log.Warnf("Long version: %s", m.Build.LongVersion)
+		}
+
+		// Old compilers for linux are listed by name
+		path := m.Build.Path
+		if path == "" {
+			path = m.Build.Name
+		}
+
+		log.Warnf("Build: %s", path)
+		log.Warnf("Sha256: %s", m.Build.Sha256)
+		log.Warnf("Keccak256: %s", m.Build.Keccak256)
+	}
+
+	log.Warnf("Downloaded from: %s", m.Url)
+	log.Warnf("Platform: %s", m.Platform)
+	log.Warnf("Installed at: %s", m.InstalledAt.Local().Format(time.RFC3339))
+	log.Warnf("Installed by: gsolc-select %s", m.GsolcSelect)
+	log.Warnf("Files: %d", len(m.Files))
+	return nil
+}
+
+func init() {
+	RegisterCmd(rootCmd, infoCmd)
+}
@@ -30,13 +30,16 @@ import (
 	ver "github.com/fabelx/go-solc-select/pkg/versions"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	"os/signal"
-	"syscall"
+	"strings"
+	"time"
 )
 
 var (
-	async bool
-	all   bool
+	async       bool
+	all         bool
+	allMatching bool
+	jobs        int
+	selfTest    bool
 )
 
 var installCmd = &cobra.Command{
@@ -46,10 +49,21 @@ var installCmd = &cobra.Command{
 
 Installs specific versions of the solc compiler.
 You can specify multiple versions separated by spaces or flag '--all/-a', which will install all available versions of the compiler.
+Use flag '--parallel/-p' to install several versions at once, flag '--jobs' limits the number of simultaneous installations
+and implies '--parallel'.
+Instead of exact versions you can specify semver constraints or 'latest', the highest available version satisfying
+the constraint is installed. Use flag '--all-matching' to install all versions satisfying the constraint.
+Use flag '--self-test' to run 'solc --version' of each installed compiler, the installation fails if the compiler
+can't be executed or reports another version.
 `,
 	Example: `  gsolc-select install 0.8.1
   gsolc-select install 0.8.1 0.4.23
+  gsolc-select install "^0.7"
+  gsolc-select install ">=0.6 <0.7" --all-matching
+  gsolc-select install latest
   gsolc-select install --all
+  gsolc-select install --all --parallel --jobs 8
+  gsolc-select install 0.8.1 --self-test
 `,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 0 && all {
@@ -62,7 +76,7 @@ You can specify multiple versions separated by spaces or flag '--all/-a', which
 }
 
 func installCompilers(cmd *cobra.Command, args []string) error {
-	availableVersions, err := ver.GetAvailable()
+	availableVersions, err := ver.GetAvailable(cmd.Context())
 	if err != nil {
 		return err
 	}
@@ -71,58 +85,136 @@ func installCompilers(cmd *cobra.Command, args []string) error {
 	var versions []string
 	for _, version := range args {
 		match := config.ValidSemVer.MatchString(version)
-		if !match {
-			return fmt.Errorf("invalid version '%s'", version)
+		if match {
+			if availableVersions[version] == "" {
+				return fmt.Errorf("'%s' is not avaliable. Run `gsolc-select versions installable`", version)
+			}
+
+			if installedVersions[version] != "" {
+				return fmt.Errorf("version '%s' is already installed. Run `gsolc-select versions`", version)
+			}
+
+			versions = appendVersion(versions, version)
+			continue
+		}
+
+		// Resolves version constraint
+		if allMatching {
+			matches, err := ver.ResolveAll(version, availableVersions)
+			if err != nil {
+				return err
+			}
+
+			for _, match := range matches {
+				if installedVersions[match] != "" {
+					log.Infof("Version %s is already installed.", match)
+					continue
+				}
+
+				versions = appendVersion(versions, match)
+			}
+
+			continue
 		}
 
-		if availableVersions[version] == "" {
-			return fmt.Errorf("'%s' is not avaliable. Run `gsolc-select versions installable`", version)
+		resolved, err := ver.Resolve(version, availableVersions)
+		if err != nil {
+			return err
 		}
 
-		if installedVersions[version] != "" {
-			return fmt.Errorf("version '%s' is already installed. Run `gsolc-select versions`", version)
+		if installedVersions[resolved] != "" {
+			return fmt.Errorf("version '%s' is already installed. Run `gsolc-select versions`", resolved)
 		}
+
+		versions = appendVersion(versions, resolved)
 	}
 
 	if all {
+		versions = nil
 		for key, _ := range availableVersions {
 			versions = append(versions, key)
 		}
+	}
+
+	if cmd.Flags().Changed("jobs") {
+		if jobs < 1 {
+			return errors.New("the --jobs flag requires a positive number")
+		}
 
-		args = versions
+		async = true
 	}
 
-	if len(args) == 0 {
+	if len(args) == 0 && len(versions) == 0 {
 		return errors.New("wrong number of args, required at least one or flag `--all/-a`")
 	}
 
+	return installVersions(cmd.Context(), versions, false)
+}
+
+// installVersions Installs passed versions of the solc compiler, installed versions are replaced if reinstall is set
+// Returns an error making the application exit with a non-zero status if any version failed to install
+func installVersions(ctx context.Context, versions []string, reinstall bool) error {
+	if len(versions) == 0 {
+		return nil
+	}
+
 	log.Warn("Installing...")
-	var installed, notInstalled []string
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
-	defer stop()
+	var results []*installer.Result
+	var err error
+	options := &installer.Options{Jobs: jobs, Progress: newProgressRenderer().observe, Reinstall: reinstall, SelfTest: selfTest}
 	if async {
-		installed, notInstalled, err = installer.AsyncInstallSolcs(ctx, args)
+		results, err = installer.AsyncInstallSolcs(ctx, versions, options)
 	} else {
-		installed, notInstalled, err = installer.InstallSolcs(ctx, args)
+		results, err = installer.InstallSolcs(ctx, versions, options)
 	}
 
 	if err != nil {
+		// The installation was interrupted, the compilers installed before are kept
+		if installed := installer.Installed(results); len(installed) != 0 {
+			log.Warnf("Installation interrupted, the installed versions are kept: %s.", strings.Join(installed, ", "))
+		}
+
 		return err
 	}
 
-	for _, version := range notInstalled {
-		log.Infof("Failed to install version %s.", version)
+	for _, result := range results {
+		if result.Err != nil {
+			log.Infof("Failed to install version %s: %s", result.Version, result.Err)
+			continue
+		}
+
+		if result.Skipped {
+			log.Infof("Version %s is already installed.", result.Version)
+			continue
+		}
+
+		log.Infof("Version %s installed from %s (%d bytes in %s).", result.Version, result.Url, result.Bytes, result.Duration.Round(time.Millisecond))
 	}
 
-	for _, version := range installed {
-		log.Infof("Version %s installed.", version)
+	if failed := installer.Failed(results); len(failed) != 0 {
+		log.Warnf("Failed to install %d of %d versions.", len(failed), len(results))
+		return &exitError{code: 1}
 	}
 
 	return nil
 }
 
+// appendVersion Appends the version to the slice if it isn't there yet
+func appendVersion(versions []string, version string) []string {
+	for _, v := range versions {
+		if v == version {
+			return versions
+		}
+	}
+
+	return append(versions, version)
+}
+
 func init() {
 	installCmd.Flags().BoolVarP(&async, "parallel", "p", false, "indicate if you want to install solc versions asynchronously")
+	installCmd.Flags().IntVar(&jobs, "jobs", config.DefaultJobs, "maximum number of solc versions installed at once in the parallel mode")
 	installCmd.Flags().BoolVarP(&all, "all", "a", false, "indicate if you want to install all available solc versions")
+	installCmd.Flags().BoolVar(&selfTest, "self-test", false, "indicate if you want to check that installed solc compilers run and report the expected version")
+	installCmd.Flags().BoolVar(&allMatching, "all-matching", false, "indicate if you want to install all available solc versions satisfying the constraints")
 	RegisterCmd(rootCmd, installCmd)
 }
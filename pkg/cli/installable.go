@@ -52,15 +52,15 @@ func getInstallableVersions(cmd *cobra.Command, args []string) error {
 	var err error
 	if windows {
 		platform := ver.WindowsPlatform{Name: config.WindowsAmd64}
-		installableVersions, err = platform.GetAvailableVersions()
+		installableVersions, err = platform.GetAvailableVersions(cmd.Context())
 	} else if linux {
 		platform := ver.LinuxPlatform{Name: config.LinuxAmd64}
-		installableVersions, err = platform.GetAvailableVersions()
+		installableVersions, err = platform.GetAvailableVersions(cmd.Context())
 	} else if mac {
 		platform := ver.MacPlatform{Name: config.MacosxAmd64}
-		installableVersions, err = platform.GetAvailableVersions()
+		installableVersions, err = platform.GetAvailableVersions(cmd.Context())
 	} else {
-		installableVersions, err = ver.GetAvailable()
+		installableVersions, err = ver.GetAvailable(cmd.Context())
 	}
 
 	if err != nil {
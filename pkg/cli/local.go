@@ -0,0 +1,112 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package cli
+
+import (
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"github.com/fabelx/go-solc-select/pkg/switcher"
+	ver "github.com/fabelx/go-solc-select/pkg/versions"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	unsetLocal bool
+)
+
+var localCmd = &cobra.Command{
+	Use:   "local",
+	Short: "Change the version of solc compiler for the current directory",
+	Long: `gsolc-select
+
+Pins an installed version of solc compiler for the current directory by writing a '.solc-version' file.
+The pinned version takes precedence over the global version in the directory and all its subdirectories.
+Without arguments prints out the version pinned for the current directory.
+`,
+	Example: `  gsolc-select local 0.8.19
+  gsolc-select local
+  gsolc-select local --unset
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: useLocalCompiler,
+}
+
+func useLocalCompiler(cmd *cobra.Command, args []string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	if unsetLocal {
+		err = os.Remove(filepath.Join(dir, config.LocalVersionFileName))
+		if os.IsNotExist(err) {
+			log.Warn("No local version set for the current directory.")
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		log.Warn("Removed local version.")
+		return nil
+	}
+
+	if len(args) == 0 {
+		path := ver.FindLocalVersionFile(dir)
+		if path == "" {
+			return &errors.NoCompilerSelected{}
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		log.Infof("Set by %s", path)
+		log.Warn(strings.TrimSpace(string(data)))
+		return nil
+	}
+
+	version := args[0]
+	match := config.ValidSemVer.MatchString(version)
+	if !match {
+		return &errors.UnknownVersionError{Version: version}
+	}
+
+	err = switcher.SwitchLocalSolc(dir, version)
+	if err != nil {
+		return err
+	}
+
+	log.Warnf("Switched local version to '%s'.", version)
+	return nil
+}
+
+func init() {
+	localCmd.Flags().BoolVarP(&unsetLocal, "unset", "u", false, "indicate if you want to remove the version pinned for the current directory")
+	RegisterCmd(rootCmd, localCmd)
+}
@@ -0,0 +1,221 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/fabelx/go-solc-select/pkg/installer"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// barWidth Width of a progress bar in characters
+	barWidth = 30
+	// redrawInterval Minimal interval between redraws of progress bars
+	redrawInterval = 100 * time.Millisecond
+	// plainInterval Interval between plain lines reporting the progress of a download
+	plainInterval = 5 * time.Second
+	// jsonInterval Interval between JSON events reporting the progress of a download
+	jsonInterval = time.Second
+)
+
+// progressRenderer Renders progress events of installations
+//
+// Renders progress bars on a terminal, plain lines otherwise and JSON events with the `--json` flag
+type progressRenderer struct {
+	mu       sync.Mutex
+	out      io.Writer
+	tty      bool
+	json     bool
+	active   []*installer.Event
+	drawn    int
+	lastDraw time.Time
+	reported map[string]time.Time
+}
+
+// newProgressRenderer Returns a renderer writing to the stdout
+func newProgressRenderer() *progressRenderer {
+	return &progressRenderer{
+		out:      os.Stdout,
+		tty:      !jsonFormat && isTerminal(os.Stdout),
+		json:     jsonFormat,
+		reported: make(map[string]time.Time),
+	}
+}
+
+// isTerminal Determines if the file is a terminal
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// observe Renders the event, the method is passed to the installer as a progress observer
+func (r *progressRenderer) observe(event *installer.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case r.json:
+		r.renderJson(event)
+	case r.tty:
+		r.renderBars(event)
+	default:
+		r.renderPlain(event)
+	}
+}
+
+// throttle Determines if a downloading event should be skipped, because the progress of the version was reported recently
+func (r *progressRenderer) throttle(event *installer.Event, interval time.Duration) bool {
+	if event.Stage != installer.StageDownloading || event.Bytes == 0 || event.Bytes == event.Total {
+		return false
+	}
+
+	if time.Since(r.reported[event.Version]) < interval {
+		return true
+	}
+
+	r.reported[event.Version] = time.Now()
+	return false
+}
+
+// renderJson Writes the event as a JSON line
+func (r *progressRenderer) renderJson(event *installer.Event) {
+	if r.throttle(event, jsonInterval) {
+		return
+	}
+
+	data := struct {
+		*installer.Event
+		Error string `json:"error,omitempty"`
+	}{Event: event}
+	if event.Err != nil {
+		data.Error = event.Err.Error()
+	}
+
+	line, _ := json.Marshal(data)
+	fmt.Fprintln(r.out, string(line))
+}
+
+// renderPlain Logs stage changes and the progress of downloads periodically
+func (r *progressRenderer) renderPlain(event *installer.Event) {
+	switch event.Stage {
+	case installer.StageDownloading:
+		if event.Bytes == 0 && event.Total == 0 {
+			log.Warnf("solc %s: downloading from %s...", event.Version, event.Url)
+			r.reported[event.Version] = time.Now()
+			return
+		}
+
+		if !r.throttle(event, plainInterval) {
+			log.Warnf("solc %s: downloading %s", event.Version, formatBytes(event.Bytes, event.Total))
+		}
+	case installer.StageFailed:
+		log.Warnf("solc %s: failed: %s", event.Version, event.Err)
+	case installer.StageResolving:
+	default:
+		log.Warnf("solc %s: %s", event.Version, event.Stage)
+	}
+}
+
+// renderBars Redraws progress bars of the versions being installed, finished versions are printed above the bars
+func (r *progressRenderer) renderBars(event *installer.Event) {
+	index := -1
+	for i, e := range r.active {
+		if e.Version == event.Version {
+			index = i
+		}
+	}
+
+	finished := event.Stage == installer.StageDone || event.Stage == installer.StageFailed
+	stageChanged := index == -1 || r.active[index].Stage != event.Stage
+	switch {
+	case index == -1:
+		r.active = append(r.active, event)
+	case finished:
+		r.active = append(r.active[:index], r.active[index+1:]...)
+	default:
+		r.active[index] = event
+	}
+
+	if !finished && !stageChanged && time.Since(r.lastDraw) < redrawInterval {
+		return
+	}
+
+	// Moves the cursor to the first bar and clears the bars
+	if r.drawn > 0 {
+		fmt.Fprintf(r.out, "\033[%dA\033[J", r.drawn)
+	}
+
+	if finished {
+		fmt.Fprintln(r.out, formatLine(event))
+	}
+
+	for _, e := range r.active {
+		fmt.Fprintln(r.out, formatLine(e))
+	}
+
+	r.drawn = len(r.active)
+	r.lastDraw = time.Now()
+}
+
+// formatLine Returns a progress bar line of the event
+func formatLine(event *installer.Event) string {
+	var bar string
+	switch {
+	case event.Stage == installer.StageFailed:
+		return fmt.Sprintf("solc %-8s failed: %s", event.Version, event.Err)
+	case event.Stage == installer.StageDownloading && event.Total > 0:
+		// The server may send more than it announced, the bar never overflows
+		filled := int(event.Bytes * barWidth / event.Total)
+		if filled > barWidth {
+			filled = barWidth
+		} else if filled < 0 {
+			filled = 0
+		}
+
+		bar = strings.Repeat("#", filled) + strings.Repeat("-", barWidth-filled)
+	case event.Stage == installer.StageDownloading || event.Stage == installer.StageResolving:
+		bar = strings.Repeat("-", barWidth)
+	default:
+		bar = strings.Repeat("#", barWidth)
+	}
+
+	return fmt.Sprintf("solc %-8s [%s] %-24s %s", event.Version, bar, formatBytes(event.Bytes, event.Total), event.Stage)
+}
+
+// formatBytes Returns the downloaded and total sizes in megabytes with the percentage
+//
+// Only the downloaded size is returned if the total is unknown or the server sent more than it announced
+func formatBytes(bytes int64, total int64) string {
+	const mb = 1 << 20
+	if total <= 0 || bytes > total {
+		return fmt.Sprintf("%.1f MB", float64(bytes)/mb)
+	}
+
+	return fmt.Sprintf("%3d%% %.1f/%.1f MB", bytes*100/total, float64(bytes)/mb, float64(total)/mb)
+}
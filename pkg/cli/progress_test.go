@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"github.com/fabelx/go-solc-select/pkg/installer"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestFormatBytes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		bytes    int64
+		total    int64
+		expected string
+	}{
+		{name: "start", bytes: 0, total: 4 << 20, expected: "  0% 0.0/4.0 MB"},
+		{name: "half", bytes: 2 << 20, total: 4 << 20, expected: " 50% 2.0/4.0 MB"},
+		{name: "complete", bytes: 4 << 20, total: 4 << 20, expected: "100% 4.0/4.0 MB"},
+		{name: "unknown total", bytes: 3 << 20, total: 0, expected: "3.0 MB"},
+		{name: "more than announced", bytes: 6 << 20, total: 4 << 20, expected: "6.0 MB"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, formatBytes(testCase.bytes, testCase.total))
+		})
+	}
+}
+
+func TestFormatLine(t *testing.T) {
+	t.Run("more than announced", func(t *testing.T) {
+		line := formatLine(&installer.Event{Version: "0.8.0", Stage: installer.StageDownloading, Bytes: 6 << 20, Total: 4 << 20})
+		assert.Contains(t, line, "["+strings.Repeat("#", barWidth)+"]")
+		assert.NotContains(t, line, "%")
+	})
+
+	t.Run("in progress", func(t *testing.T) {
+		line := formatLine(&installer.Event{Version: "0.8.0", Stage: installer.StageDownloading, Bytes: 2 << 20, Total: 4 << 20})
+		assert.Contains(t, line, "["+strings.Repeat("#", barWidth/2)+strings.Repeat("-", barWidth/2)+"]")
+		assert.Contains(t, line, " 50% 2.0/4.0 MB")
+	})
+}
@@ -0,0 +1,95 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package cli
+
+import (
+	"errors"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	ver "github.com/fabelx/go-solc-select/pkg/versions"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"runtime"
+)
+
+var refreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Refresh cached lists of solc versions",
+	Long: `gsolc-select
+
+Revalidates the cached lists of available solc versions with the server regardless of their age.
+Lists are cached in ~/.gsolc-select/cache and used by other commands until they are older than '--cache-ttl',
+with the flag '--offline' only the cached lists are used.
+`,
+	Example: `  gsolc-select refresh
+  gsolc-select refresh --all
+  gsolc-select versions installable --offline`,
+	Args: cobra.NoArgs,
+	RunE: refreshLists,
+}
+
+func refreshLists(cmd *cobra.Command, args []string) error {
+	if config.Offline {
+		return errors.New("lists can't be refreshed in the offline mode")
+	}
+
+	var platforms []ver.Platform
+	switch {
+	case all:
+		platforms = []ver.Platform{
+			&ver.LinuxPlatform{Name: config.LinuxAmd64},
+			&ver.MacPlatform{Name: config.MacosxAmd64},
+			&ver.WindowsPlatform{Name: config.WindowsAmd64},
+		}
+	case windows:
+		platforms = []ver.Platform{&ver.WindowsPlatform{Name: config.WindowsAmd64}}
+	case linux:
+		platforms = []ver.Platform{&ver.LinuxPlatform{Name: config.LinuxAmd64}}
+	case mac:
+		platforms = []ver.Platform{&ver.MacPlatform{Name: config.MacosxAmd64}}
+	default:
+		platform, err := ver.GetPlatform(runtime.GOOS)
+		if err != nil {
+			return err
+		}
+
+		platforms = []ver.Platform{platform}
+	}
+
+	for _, platform := range platforms {
+		err := ver.Refresh(cmd.Context(), platform)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Warn("Lists of solc versions refreshed.")
+	return nil
+}
+
+func init() {
+	refreshCmd.Flags().BoolVarP(&all, "all", "a", false, "indicate if you want to refresh lists of solc versions for all platforms")
+	refreshCmd.Flags().BoolVarP(&windows, "windows", "w", false, "indicate if you want to refresh the list of solc versions for windows OS")
+	refreshCmd.Flags().BoolVarP(&linux, "linux", "l", false, "indicate if you want to refresh the list of solc versions for linux OS")
+	refreshCmd.Flags().BoolVarP(&mac, "mac", "m", false, "indicate if you want to refresh the list of solc versions for mac OS")
+	refreshCmd.MarkFlagsMutuallyExclusive("all", "windows", "linux", "mac")
+	RegisterCmd(rootCmd, refreshCmd)
+}
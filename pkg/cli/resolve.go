@@ -0,0 +1,106 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/fabelx/go-solc-select/pkg/project"
+	ver "github.com/fabelx/go-solc-select/pkg/versions"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+var (
+	root string
+)
+
+var resolveCmd = &cobra.Command{
+	Use:   "resolve <file.sol>...",
+	Short: "Resolve solc versions for contract entry points",
+	Long: `gsolc-select
+
+Follows imports of the entry files (relative paths and remappings from 'remappings.txt' of the project root)
+and prints out the combined version pragmas of each compilation unit, the newest installable version satisfying them
+and the minimum number of compiler runs needed to compile all entry files.
+Use the --json flag to get the result in JSON format.
+`,
+	Example: `  gsolc-select resolve contracts/Token.sol
+  gsolc-select resolve src/A.sol src/B.sol --root . --json
+`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: resolveSources,
+}
+
+func resolveSources(cmd *cobra.Command, args []string) error {
+	availableVersions, err := ver.GetAvailable(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	resolution, err := project.Resolve(root, args, ver.SortVersions(availableVersions))
+	if err != nil {
+		return err
+	}
+
+	if jsonFormat {
+		data, err := json.MarshalIndent(resolution, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+
+	for _, unit := range resolution.Units {
+		log.Warnf("%s: %d file(s), %s", unit.Entry, len(unit.Files), strings.Join(unit.Pragmas, ", "))
+		for _, missing := range unit.Missing {
+			log.Warnf("  missing import: %s", missing)
+		}
+
+		for _, e := range unit.Errors {
+			log.Warnf("  %s", e)
+		}
+
+		if len(unit.Versions) != 0 {
+			log.Infof("  satisfying versions: %s", strings.Join(unit.Versions, ", "))
+			log.Warnf("  newest satisfying version: %s", unit.Versions[len(unit.Versions)-1])
+		}
+	}
+
+	for _, entry := range resolution.Unresolved {
+		log.Warnf("No installable version satisfies %s", entry)
+	}
+
+	for _, job := range resolution.Jobs {
+		log.Warnf("solc %s: %s", job.Version, strings.Join(job.Entries, ", "))
+	}
+
+	return nil
+}
+
+func init() {
+	resolveCmd.Flags().StringVar(&root, "root", ".", "project root, import paths and remappings are resolved relative to it")
+	RegisterCmd(rootCmd, resolveCmd)
+}
@@ -22,11 +22,16 @@ go-solc-select is a tool written in Golang for managing and switching between ve
 package cli
 
 import (
+	"context"
+	"fmt"
 	"github.com/fabelx/go-solc-select/pkg/config"
+	"github.com/fabelx/go-solc-select/pkg/installer"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	easy "github.com/t-tomalak/logrus-easy-formatter"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 var (
@@ -46,11 +51,23 @@ Allows users to installer and quickly switch between Solidity compiler versions
 `,
 	Example: `  gsolc-select versions current - get current solc version
   gsolc-select install 0.8.1 - install a solc compiler
+  gsolc-select install "^0.7" - install the latest solc compiler satisfying the constraint
+  gsolc-select install --all --jobs 8 - install all solc compilers, at most 8 at once
   gsolc-select use 0.8.1 - switch current version to 0.8.1
+  gsolc-select use --auto - install and switch to the version required by Solidity sources of the current directory
+  gsolc-select local 0.8.1 - pin version 0.8.1 for the current directory
+  eval "$(gsolc-select shell 0.8.1)" - use version 0.8.1 in the current shell session
+  gsolc-select exec 0.8.1 -- slither . - run a command with version 0.8.1
   gsolc-select uninstall 0.8.1 - remove solc compiler
   gsolc-select uninstall 0.8.1 0.8.17 -v - remove solc compilers verbose
   gsolc-select versions - get installed solc compiler versions
   gsolc-select versions installable - get installable solc compiler versions for current platform (OS)
+  gsolc-select refresh - refresh cached lists of installable solc compiler versions
+  gsolc-select info 0.8.1 - show where the installed solc compiler 0.8.1 came from
+  gsolc-select verify --repair - verify installed solc compilers and reinstall broken ones
+  gsolc-select trust --reset 0.8.1 - forget the pinned checksums of version 0.8.1
+  gsolc-select scan ./contracts - report solc compiler versions required by Solidity sources
+  gsolc-select resolve contracts/Token.sol - resolve solc compiler version for a contract and its imports
 `,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Setup logging
@@ -85,6 +102,11 @@ Allows users to installer and quickly switch between Solidity compiler versions
 			}
 		}
 
+		// Sweeps compilers left in the staging folder by crashed installations
+		if err := installer.CleanStaging(); err != nil {
+			log.Infof("Failed to clean the staging folder: %s", err)
+		}
+
 		return nil
 	},
 }
@@ -92,6 +114,28 @@ Allows users to installer and quickly switch between Solidity compiler versions
 func init() {
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "s", false, "indicate if you want for log details")
 	rootCmd.PersistentFlags().BoolVarP(&jsonFormat, "json", "j", false, "indicate if you want to use json format for logging details")
+	rootCmd.PersistentFlags().DurationVar(&config.HttpTimeout, "http-timeout", config.HttpTimeout, "timeout of connecting to a server and waiting for data")
+	rootCmd.PersistentFlags().StringSliceVar(&config.SolcMirrors, "mirror", config.SolcMirrors, fmt.Sprintf("base urls of repositories with solc compilers tried in order, also set by %s", config.SolcMirrorsEnv))
+	rootCmd.PersistentFlags().StringSliceVar(&config.OldSolcMirrors, "old-mirror", config.OldSolcMirrors, fmt.Sprintf("base urls of repositories with old solc compilers for linux tried in order, also set by %s", config.OldSolcMirrorsEnv))
+	rootCmd.PersistentFlags().StringSliceVar(&config.OldSolcListMirrors, "old-list-mirror", config.OldSolcListMirrors, fmt.Sprintf("urls of lists of old solc compilers for linux tried in order, also set by %s", config.OldSolcListMirrorsEnv))
+	rootCmd.PersistentFlags().StringVar(&config.HttpConfigPath, "http-config", config.HttpConfigPath, fmt.Sprintf("path to the JSON file with headers sent to repositories and their mirrors, also set by %s", config.HttpConfigEnv))
+	rootCmd.PersistentFlags().StringVar(&config.CaBundle, "ca-bundle", config.CaBundle, fmt.Sprintf("path to a PEM bundle of CAs trusted in addition to the system ones, also set by %s", config.CaBundleEnv))
+	rootCmd.PersistentFlags().StringVar(&config.ClientCert, "client-cert", config.ClientCert, fmt.Sprintf("path to a PEM client certificate presented to repositories, also set by %s", config.ClientCertEnv))
+	rootCmd.PersistentFlags().StringVar(&config.ClientKey, "client-key", config.ClientKey, fmt.Sprintf("path to a PEM key of the client certificate, also set by %s", config.ClientKeyEnv))
+	rootCmd.PersistentFlags().StringVar(&config.Netrc, "netrc-file", config.Netrc, fmt.Sprintf("path to a netrc file with credentials of repositories, also set by %s", config.NetrcEnv))
+	rootCmd.PersistentFlags().BoolVar(&config.Offline, "offline", config.Offline, "indicate if you want to use only cached lists of solc versions")
+	rootCmd.PersistentFlags().DurationVar(&config.CacheTTL, "cache-ttl", config.CacheTTL, "age after which cached lists of solc versions are revalidated with the server")
+	rootCmd.PersistentFlags().DurationVar(&config.LockTimeout, "lock-timeout", config.LockTimeout, "time to wait for a lock held by another gsolc-select process, negative value means waiting indefinitely")
+	rootCmd.PersistentFlags().IntVar(&config.HttpRetries, "http-retries", config.HttpRetries, "number of retries of a request failed due to a server or connection error")
+}
+
+// exitError Makes the application exit with a specific status code without logging a message
+type exitError struct {
+	code int
+}
+
+func (r *exitError) Error() string {
+	return fmt.Sprintf("exit status %d", r.code)
 }
 
 // RegisterCmd Registers a new command under the root command
@@ -101,7 +145,15 @@ func RegisterCmd(rootCommand *cobra.Command, command *cobra.Command) {
 
 // Execute the entrypoint called by main.go
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	// Interruption cancels the context of the command and aborts network requests
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		if exitErr, ok := err.(*exitError); ok {
+			os.Exit(exitErr.code)
+		}
+
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,109 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/fabelx/go-solc-select/pkg/project"
+	ver "github.com/fabelx/go-solc-select/pkg/versions"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+var scanCmd = &cobra.Command{
+	Use:   "scan [dir]",
+	Short: "Report solc versions required by Solidity sources",
+	Long: `gsolc-select
+
+Scans Solidity source files of the directory (the current directory by default) and prints out
+version pragmas of each file, installable versions satisfying all files, the newest of them
+and files whose version pragmas are mutually incompatible.
+Hidden directories and 'node_modules' are skipped. Use the --json flag to get the report in JSON format.
+`,
+	Example: `  gsolc-select scan
+  gsolc-select scan ./contracts --json
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: scanSources,
+}
+
+func scanSources(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) != 0 {
+		dir = args[0]
+	}
+
+	files, err := project.Scan(dir)
+	if err != nil {
+		return err
+	}
+
+	availableVersions, err := ver.GetAvailable(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	report := project.NewReport(files, ver.SortVersions(availableVersions))
+	if jsonFormat {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+
+	for _, file := range report.Files {
+		switch {
+		case file.Error != "":
+			log.Warnf("%s: %s", file.Path, file.Error)
+		case len(file.Pragmas) == 0:
+			log.Warnf("%s: no version pragma", file.Path)
+		default:
+			log.Warnf("%s: %s", file.Path, strings.Join(file.Pragmas, ", "))
+		}
+	}
+
+	for _, path := range report.Unsatisfiable {
+		log.Warnf("No installable version satisfies %s", path)
+	}
+
+	for _, conflict := range report.Conflicts {
+		log.Warnf("Incompatible: %s and %s", conflict[0], conflict[1])
+	}
+
+	if report.Newest == "" {
+		log.Warn("No installable version satisfies all files.")
+		return nil
+	}
+
+	log.Warnf("Satisfying versions: %s", strings.Join(report.Versions, ", "))
+	log.Warnf("Newest satisfying version: %s", report.Newest)
+	return nil
+}
+
+func init() {
+	RegisterCmd(rootCmd, scanCmd)
+}
@@ -0,0 +1,146 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	ver "github.com/fabelx/go-solc-select/pkg/versions"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+var (
+	shell      string
+	unsetShell bool
+)
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Change the version of solc compiler for the current shell session",
+	Long: `gsolc-select
+
+Prints out a command which sets the SOLC_VERSION environment variable for the current shell session.
+The version set this way takes precedence over the local and global versions.
+Supported shells: bash, zsh, fish and powershell. By default the shell is detected from the SHELL environment variable.
+`,
+	Example: `  eval "$(gsolc-select shell 0.7.6)"
+  gsolc-select shell 0.7.6 --shell fish | source
+  gsolc-select shell 0.7.6 --shell powershell | Invoke-Expression
+  eval "$(gsolc-select shell --unset)"
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if unsetShell {
+			return cobra.NoArgs(cmd, args)
+		}
+
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: useShellCompiler,
+}
+
+func useShellCompiler(cmd *cobra.Command, args []string) error {
+	name := shell
+	if name == "" {
+		name = detectShell()
+	}
+
+	if unsetShell {
+		line, err := unsetEnvCommand(name, config.SolcVersionEnv)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), line)
+		return nil
+	}
+
+	version := args[0]
+	match := config.ValidSemVer.MatchString(version)
+	if !match {
+		return &errors.UnknownVersionError{Version: version}
+	}
+
+	installedVersions := ver.GetInstalled()
+	if installedVersions[version] == "" {
+		return &errors.NotInstalledError{Version: version}
+	}
+
+	line, err := setEnvCommand(name, config.SolcVersionEnv, version)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), line)
+	return nil
+}
+
+// detectShell Returns the name of the user's shell
+func detectShell() string {
+	if path := os.Getenv("SHELL"); path != "" {
+		return strings.TrimSuffix(filepath.Base(path), ".exe")
+	}
+
+	if runtime.GOOS == "windows" {
+		return "powershell"
+	}
+
+	return "bash"
+}
+
+// setEnvCommand Returns a command setting the environment variable in the specific shell
+func setEnvCommand(shell string, name string, value string) (string, error) {
+	switch shell {
+	case "bash", "zsh", "sh":
+		return fmt.Sprintf("export %s=%s", name, value), nil
+	case "fish":
+		return fmt.Sprintf("set -gx %s %s", name, value), nil
+	case "powershell", "pwsh":
+		return fmt.Sprintf("$env:%s = \"%s\"", name, value), nil
+	default:
+		return "", fmt.Errorf("unsupported shell '%s'", shell)
+	}
+}
+
+// unsetEnvCommand Returns a command removing the environment variable in the specific shell
+func unsetEnvCommand(shell string, name string) (string, error) {
+	switch shell {
+	case "bash", "zsh", "sh":
+		return fmt.Sprintf("unset %s", name), nil
+	case "fish":
+		return fmt.Sprintf("set -e %s", name), nil
+	case "powershell", "pwsh":
+		return fmt.Sprintf("Remove-Item Env:%s -ErrorAction SilentlyContinue", name), nil
+	default:
+		return "", fmt.Errorf("unsupported shell '%s'", shell)
+	}
+}
+
+func init() {
+	shellCmd.Flags().StringVar(&shell, "shell", "", "shell to print the command for: bash, zsh, fish or powershell")
+	shellCmd.Flags().BoolVarP(&unsetShell, "unset", "u", false, "indicate if you want to remove the version set for the current shell session")
+	RegisterCmd(rootCmd, shellCmd)
+}
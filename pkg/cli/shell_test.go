@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetEnvCommand(t *testing.T) {
+	testCases := []struct {
+		shell    string
+		expected string
+	}{
+		{shell: "bash", expected: "export SOLC_VERSION=0.7.6"},
+		{shell: "zsh", expected: "export SOLC_VERSION=0.7.6"},
+		{shell: "sh", expected: "export SOLC_VERSION=0.7.6"},
+		{shell: "fish", expected: "set -gx SOLC_VERSION 0.7.6"},
+		{shell: "powershell", expected: "$env:SOLC_VERSION = \"0.7.6\""},
+		{shell: "pwsh", expected: "$env:SOLC_VERSION = \"0.7.6\""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.shell, func(t *testing.T) {
+			result, err := setEnvCommand(testCase.shell, "SOLC_VERSION", "0.7.6")
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, result)
+		})
+	}
+
+	t.Run("unsupported shell", func(t *testing.T) {
+		_, err := setEnvCommand("tcsh", "SOLC_VERSION", "0.7.6")
+		assert.EqualError(t, err, "unsupported shell 'tcsh'")
+	})
+}
+
+func TestUnsetEnvCommand(t *testing.T) {
+	testCases := []struct {
+		shell    string
+		expected string
+	}{
+		{shell: "bash", expected: "unset SOLC_VERSION"},
+		{shell: "zsh", expected: "unset SOLC_VERSION"},
+		{shell: "sh", expected: "unset SOLC_VERSION"},
+		{shell: "fish", expected: "set -e SOLC_VERSION"},
+		{shell: "powershell", expected: "Remove-Item Env:SOLC_VERSION -ErrorAction SilentlyContinue"},
+		{shell: "pwsh", expected: "Remove-Item Env:SOLC_VERSION -ErrorAction SilentlyContinue"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.shell, func(t *testing.T) {
+			result, err := unsetEnvCommand(testCase.shell, "SOLC_VERSION")
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, result)
+		})
+	}
+
+	t.Run("unsupported shell", func(t *testing.T) {
+		_, err := unsetEnvCommand("tcsh", "SOLC_VERSION")
+		assert.EqualError(t, err, "unsupported shell 'tcsh'")
+	})
+}
@@ -0,0 +1,115 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"github.com/fabelx/go-solc-select/pkg/trust"
+	ver "github.com/fabelx/go-solc-select/pkg/versions"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"runtime"
+	"time"
+)
+
+var reset bool
+
+var trustCmd = &cobra.Command{
+	Use:   "trust",
+	Short: "Show or reset known checksums of solc versions",
+	Long: `gsolc-select
+
+Prints out the checksums of solc versions for the current platform pinned when the versions were first seen.
+Installation of a version whose checksums in the list of available versions differ from the pinned ones is refused,
+since the repository or its mirror may have been tampered with.
+Use flag '--reset' to forget the checksums of versions once the change is verified to be legitimate,
+the checksums seen by the next installation are pinned instead.
+`,
+	Example: `  gsolc-select trust
+  gsolc-select trust 0.8.1
+  gsolc-select trust --reset 0.8.1
+`,
+	RunE: trustVersions,
+}
+
+func trustVersions(cmd *cobra.Command, args []string) error {
+	platform, err := ver.GetPlatform(runtime.GOOS)
+	if err != nil {
+		return err
+	}
+
+	if reset {
+		if len(args) == 0 {
+			return errors.New("the --reset flag requires at least one version")
+		}
+
+		for _, version := range args {
+			known, err := trust.Reset(cmd.Context(), platform.GetName(), version)
+			if err != nil {
+				return err
+			}
+
+			if !known {
+				log.Warnf("No checksums of version %s are known.", version)
+				continue
+			}
+
+			log.Warnf("Checksums of version %s are forgotten.", version)
+		}
+
+		return nil
+	}
+
+	known, err := trust.GetKnown(platform.GetName())
+	if err != nil {
+		return err
+	}
+
+	// Prints out all known versions if none specified
+	versions := make(map[string]string)
+	if len(args) == 0 {
+		for version := range known {
+			versions[version] = version
+		}
+	}
+
+	for _, version := range args {
+		if known[version] == nil {
+			return fmt.Errorf("no checksums of version '%s' are known", version)
+		}
+
+		versions[version] = version
+	}
+
+	for _, version := range ver.SortVersions(versions) {
+		hashes := known[version.Original()]
+		log.Warnf("%s sha256:%s keccak256:%s first seen %s", version.Original(), hashes.Sha256, hashes.Keccak256, hashes.FirstSeen.Local().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+func init() {
+	trustCmd.Flags().BoolVar(&reset, "reset", false, "indicate if you want to forget the checksums of the versions")
+	RegisterCmd(rootCmd, trustCmd)
+}
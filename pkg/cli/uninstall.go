@@ -38,9 +38,12 @@ var uninstallCmd = &cobra.Command{
 
 Removes certain versions of the installed solc compiler.
 You can specify multiple versions separated by spaces or 'all', which will remove all installed versions of the compiler.
+Instead of exact versions you can specify semver constraints, the highest installed version satisfying
+the constraint is removed. Use flag '--all-matching' to remove all versions satisfying the constraint.
 `,
 	Example: `  gsolc-select uninstall 0.6.5
   gsolc-select uninstall 0.7.2 0.4.1
+  gsolc-select uninstall "<0.5" --all-matching
   gsolc-select install all
 `,
 	Args: func(cmd *cobra.Command, args []string) error {
@@ -55,29 +58,52 @@ You can specify multiple versions separated by spaces or 'all', which will remov
 
 func uninstallCompilers(cmd *cobra.Command, args []string) error {
 	var installedVersions = ver.GetInstalled()
+	var versions []string
 	for _, version := range args {
 		match := config.ValidSemVer.MatchString(version)
-		if !match {
-			return fmt.Errorf("invalid version '%s'", version)
+		if match {
+			if installedVersions[version] == "" {
+				return fmt.Errorf("'%s' is not installed. Run `gsolc-select versions`", version)
+			}
+
+			versions = appendVersion(versions, version)
+			continue
+		}
+
+		// Resolves version constraint
+		if allMatching {
+			matches, err := ver.ResolveAll(version, installedVersions)
+			if err != nil {
+				return err
+			}
+
+			for _, match := range matches {
+				versions = appendVersion(versions, match)
+			}
+
+			continue
 		}
 
-		if installedVersions[version] == "" {
-			return fmt.Errorf("'%s' is not installed. Run `gsolc-select versions`", version)
+		resolved, err := ver.Resolve(version, installedVersions)
+		if err != nil {
+			return err
 		}
+
+		versions = appendVersion(versions, resolved)
 	}
 
 	if all {
 		for key, _ := range installedVersions {
-			args = append(args, key)
+			versions = append(versions, key)
 		}
 	}
 
-	if len(args) == 0 {
+	if len(versions) == 0 {
 		return nil
 	}
 
 	log.Warn("Uninstalling...")
-	uninstalled, notUninstalled, err := uninstaller.UninstallSolcs(args)
+	uninstalled, notUninstalled, err := uninstaller.UninstallSolcs(versions)
 	if err != nil {
 		return err
 	}
@@ -95,5 +121,6 @@ func uninstallCompilers(cmd *cobra.Command, args []string) error {
 
 func init() {
 	uninstallCmd.Flags().BoolVarP(&all, "all", "a", false, "indicate if you want to uninstall all installed solc versions")
+	uninstallCmd.Flags().BoolVar(&allMatching, "all-matching", false, "indicate if you want to uninstall all installed solc versions satisfying the constraints")
 	RegisterCmd(rootCmd, uninstallCmd)
 }
@@ -22,15 +22,21 @@ go-solc-select is a tool written in Golang for managing and switching between ve
 package cli
 
 import (
+	"context"
+	"fmt"
 	"github.com/fabelx/go-solc-select/internal/errors"
 	"github.com/fabelx/go-solc-select/pkg/config"
+	"github.com/fabelx/go-solc-select/pkg/project"
 	"github.com/fabelx/go-solc-select/pkg/switcher"
+	ver "github.com/fabelx/go-solc-select/pkg/versions"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"path/filepath"
 )
 
 var (
 	install bool
+	auto    bool
 )
 
 var useCmd = &cobra.Command{
@@ -40,37 +46,159 @@ var useCmd = &cobra.Command{
 
 Switch between installed versions of solc compiler. 
 Using the -i / --installer flag automatically installer the required compiler version.
+Instead of an exact version you can specify a semver constraint or 'latest', the highest version satisfying the constraint is used.
+Using the --auto flag picks the newest version satisfying version pragmas of Solidity sources of the directory
+(the current directory by default), installs it if needed and switches to it. The version is pinned locally
+if the directory has a '.solc-version' file (see 'gsolc-select local'), otherwise the global version is changed,
+'.solc-version' files of parent directories are ignored.
 `,
 	Example: `  gsolc-select use 0.4.12
   gsolc-select use -i 0.4.13
+  gsolc-select use "^0.8.0"
+  gsolc-select use -i latest
+  gsolc-select use --auto
+  gsolc-select use --auto ./contracts
 `,
-	Args: cobra.ExactArgs(1),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if auto {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	RunE: useCompiler,
 }
 
 func useCompiler(cmd *cobra.Command, args []string) error {
-	version := args[0]
-	match := config.ValidSemVer.MatchString(version)
-	if !match {
-		return &errors.UnknownVersionError{Version: version}
+	if auto {
+		dir := "."
+		if len(args) != 0 {
+			dir = args[0]
+		}
+
+		return useAutoCompiler(cmd.Context(), dir)
+	}
+
+	version, err := resolveUseVersion(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	err = switcher.SwitchSolc(version)
+	if err != nil {
+		return err
+	}
+
+	log.Warnf("Switched global version to '%s'.", version)
+	return nil
+}
+
+// useAutoCompiler Switches to the newest version satisfying version pragmas of Solidity sources of the directory
+func useAutoCompiler(ctx context.Context, dir string) error {
+	availableVersions, err := ver.GetAvailable(ctx)
+	if err != nil {
+		return err
 	}
 
-	if install {
-		if err := installCompilers(cmd, args); err != nil {
+	version, err := autoVersion(dir, availableVersions)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Version %s satisfies all Solidity sources.", version)
+	if ver.GetInstalled()[version] == "" {
+		if err := installVersions(ctx, []string{version}, false); err != nil {
 			return err
 		}
 	}
 
-	err := switcher.SwitchSolc(version)
+	local, err := switchAuto(dir, version)
 	if err != nil {
 		return err
 	}
 
-	log.Warnf("Switched global version to '%s'.", version)
+	if local {
+		log.Warnf("Switched local version to '%s'.", version)
+	} else {
+		log.Warnf("Switched global version to '%s'.", version)
+	}
+
 	return nil
 }
 
+// autoVersion Returns the newest of the available versions satisfying version pragmas of Solidity sources of the directory
+func autoVersion(dir string, availableVersions map[string]string) (string, error) {
+	files, err := project.Scan(dir)
+	if err != nil {
+		return "", err
+	}
+
+	if len(files) == 0 {
+		return "", fmt.Errorf("no Solidity sources found in '%s'", dir)
+	}
+
+	report := project.NewReport(files, ver.SortVersions(availableVersions))
+	if report.Newest == "" {
+		return "", fmt.Errorf("no installable version satisfies all Solidity sources of '%s'. Run `gsolc-select scan %s`", dir, dir)
+	}
+
+	return report.Newest, nil
+}
+
+// switchAuto Pins the version in the project pin file if the directory has one, otherwise changes the global version
+// Returns true if the version was pinned locally
+//
+// The directory is the project root, pin files of its parent directories (e.g. the home directory) are ignored
+func switchAuto(dir string, version string) (bool, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false, err
+	}
+
+	if path := ver.FindLocalVersionFileWithin(absDir, absDir); path != "" {
+		return true, switcher.SwitchLocalSolc(filepath.Dir(path), version)
+	}
+
+	return false, switcher.SwitchSolc(version)
+}
+
+// resolveUseVersion Returns the version to switch to, installs it if the -i / --install flag is used
+func resolveUseVersion(ctx context.Context, constraint string) (string, error) {
+	installedVersions := ver.GetInstalled()
+	if !install {
+		// Exact versions are checked by the switcher
+		if config.ValidSemVer.MatchString(constraint) {
+			return constraint, nil
+		}
+
+		return ver.Resolve(constraint, installedVersions)
+	}
+
+	availableVersions, err := ver.GetAvailable(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	version, err := ver.Resolve(constraint, availableVersions)
+	if err != nil {
+		if config.ValidSemVer.MatchString(constraint) {
+			return "", &errors.UnknownVersionError{Version: constraint}
+		}
+
+		return "", err
+	}
+
+	if installedVersions[version] == "" {
+		if err := installVersions(ctx, []string{version}, false); err != nil {
+			return "", err
+		}
+	}
+
+	return version, nil
+}
+
 func init() {
 	useCmd.Flags().BoolVarP(&install, "install", "i", false, "indicate if you want to automatically installer versions that are not installed")
+	useCmd.Flags().BoolVar(&auto, "auto", false, "indicate if you want to pick the version required by Solidity sources of the directory")
 	RegisterCmd(rootCmd, useCmd)
 }
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// useTestDirs Points the application dirs to a temporary directory with fake installed versions until the test ends
+func useTestDirs(t *testing.T, versions ...string) {
+	solcDir, artifacts, locks, current := config.SolcDir, config.SolcArtifacts, config.SolcLocks, config.CurrentVersionFilePath
+	config.SolcDir = t.TempDir()
+	config.SolcArtifacts = filepath.Join(config.SolcDir, "artifacts")
+	config.SolcLocks = filepath.Join(config.SolcDir, "locks")
+	config.CurrentVersionFilePath = filepath.Join(config.SolcDir, "global-version")
+	t.Cleanup(func() {
+		config.SolcDir, config.SolcArtifacts, config.SolcLocks, config.CurrentVersionFilePath = solcDir, artifacts, locks, current
+	})
+
+	for _, version := range versions {
+		name := fmt.Sprintf("solc-%s", version)
+		os.MkdirAll(filepath.Join(config.SolcArtifacts, name), 0755)
+		os.WriteFile(filepath.Join(config.SolcArtifacts, name, name), []byte(name), 0755)
+	}
+}
+
+// writeFiles Creates the files with the content in the directory
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestAutoVersion(t *testing.T) {
+	available := map[string]string{"0.7.6": "0.7.6", "0.8.3": "0.8.3", "0.8.19": "0.8.19"}
+
+	t.Run("newest version satisfying all pragmas", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"contracts/Token.sol": "pragma solidity ^0.8.0;",
+			"contracts/Math.sol":  "pragma solidity >=0.7.0 <0.8.10;",
+		})
+		version, err := autoVersion(dir, available)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.8.3", version)
+	})
+
+	t.Run("conflicting pragmas", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"A.sol": "pragma solidity ^0.8.0;",
+			"B.sol": "pragma solidity ^0.7.0;",
+		})
+		_, err := autoVersion(dir, available)
+		assert.Error(t, err)
+	})
+
+	t.Run("no sources", func(t *testing.T) {
+		_, err := autoVersion(t.TempDir(), available)
+		assert.Error(t, err)
+	})
+}
+
+func TestSwitchAuto(t *testing.T) {
+	t.Run("pins the version in the project pin file", func(t *testing.T) {
+		useTestDirs(t, "0.8.3")
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{config.LocalVersionFileName: "0.7.6\n"})
+		local, err := switchAuto(dir, "0.8.3")
+		assert.NoError(t, err)
+		assert.True(t, local)
+
+		data, _ := os.ReadFile(filepath.Join(dir, config.LocalVersionFileName))
+		assert.Equal(t, "0.8.3\n", string(data))
+		assert.NoFileExists(t, config.CurrentVersionFilePath)
+	})
+
+	t.Run("ignores pin files above the project", func(t *testing.T) {
+		useTestDirs(t, "0.8.3")
+		home := t.TempDir()
+		dir := filepath.Join(home, "project")
+		writeFiles(t, home, map[string]string{
+			config.LocalVersionFileName: "0.7.6\n",
+			"project/Token.sol":         "pragma solidity ^0.8.0;",
+		})
+		local, err := switchAuto(dir, "0.8.3")
+		assert.NoError(t, err)
+		assert.False(t, local)
+
+		data, _ := os.ReadFile(config.CurrentVersionFilePath)
+		assert.Equal(t, "0.8.3", string(data))
+		data, _ = os.ReadFile(filepath.Join(home, config.LocalVersionFileName))
+		assert.Equal(t, "0.7.6\n", string(data))
+	})
+}
@@ -0,0 +1,112 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package cli
+
+import (
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/pkg/verifier"
+	ver "github.com/fabelx/go-solc-select/pkg/versions"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var repair bool
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify installed solc versions",
+	Long: `gsolc-select
+
+Recomputes the checksums of the files of installed solc versions and compares them with the checksums recorded
+at installation. The compiler binary is also compared with the checksums pinned when its build was first seen
+(see 'gsolc-select trust'), so a binary replaced along with the recorded checksums doesn't pass. Versions installed
+without recorded checksums are compared with the pinned checksums, or with the list of available versions
+if the version was never pinned.
+You can specify multiple versions separated by spaces, all installed versions are verified by default.
+Use flag '--repair' to reinstall the versions that failed verification.
+`,
+	Example: `  gsolc-select verify
+  gsolc-select verify 0.8.1 0.4.23
+  gsolc-select verify --repair
+`,
+	RunE: verifyVersions,
+}
+
+func verifyVersions(cmd *cobra.Command, args []string) error {
+	versions := args
+	if len(versions) == 0 {
+		for _, version := range ver.SortVersions(ver.GetInstalled()) {
+			versions = append(versions, version.Original())
+		}
+	}
+
+	if len(versions) == 0 {
+		log.Warn("No solc versions are installed.")
+		return nil
+	}
+
+	results, err := verifier.VerifySolcs(cmd.Context(), versions)
+	if err != nil {
+		return err
+	}
+
+	var broken []string
+	for _, result := range results {
+		if result.Err == nil {
+			log.Warnf("Version %s is intact (verified against the %s checksums).", result.Version, result.Source)
+			continue
+		}
+
+		log.Warnf("Version %s failed verification: %s", result.Version, result.Err)
+		// Versions that aren't installed can't be repaired
+		if _, ok := result.Err.(*errors.NotInstalledError); !ok {
+			broken = append(broken, result.Version)
+		}
+	}
+
+	failed := verifier.Failed(results)
+	if len(failed) == 0 {
+		return nil
+	}
+
+	if !repair || len(broken) == 0 {
+		log.Warnf("%d of %d versions failed verification.", len(failed), len(results))
+		return &exitError{code: 1}
+	}
+
+	err = installVersions(cmd.Context(), broken, true)
+	if err != nil {
+		return err
+	}
+
+	// Repaired versions are verified by the installation, the versions that aren't installed still fail
+	if len(broken) != len(failed) {
+		return &exitError{code: 1}
+	}
+
+	return nil
+}
+
+func init() {
+	verifyCmd.Flags().BoolVar(&repair, "repair", false, "indicate if you want to reinstall the versions that failed verification")
+	RegisterCmd(rootCmd, verifyCmd)
+}
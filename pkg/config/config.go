@@ -25,6 +25,9 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
+	"time"
+	"unicode"
 )
 
 // HomeDir Home directory of the current user
@@ -36,9 +39,45 @@ var SolcDir = filepath.Join(HomeDir, ".gsolc-select")
 // SolcArtifacts Directory contains solc compilers
 var SolcArtifacts = filepath.Join(SolcDir, "artifacts")
 
+// ManifestFileName The name of the file describing an installed compiler, stored in its folder in artifacts
+const ManifestFileName = "manifest.json"
+
+// SolcDownloads Directory contains partially downloaded solc compilers
+var SolcDownloads = filepath.Join(SolcDir, "downloads")
+
+// SolcStaging Directory contains solc compilers being installed, they are moved to artifacts once complete
+var SolcStaging = filepath.Join(SolcDir, "staging")
+
+// StagingTTL Age after which an entry of the staging directory is considered left over by a crashed installation
+const StagingTTL = time.Hour
+
+// SolcLocks Directory contains lock files coordinating concurrent gsolc-select processes
+var SolcLocks = filepath.Join(SolcDir, "locks")
+
+// SolcCache Directory contains cached lists of available solc compilers
+var SolcCache = filepath.Join(SolcDir, "cache")
+
+// KnownHashesPath The file with checksums of every solc build seen, pinned on first sight
+var KnownHashesPath = filepath.Join(SolcDir, "known-hashes.json")
+
+// CacheTTL Age after which a cached list of compilers is revalidated with the server
+var CacheTTL = time.Hour
+
+// Offline Use only cached lists of compilers and never request them from the server
+var Offline = false
+
 // CurrentVersionFilePath The name of the file that contains the current version
 var CurrentVersionFilePath = filepath.Join(SolcDir, "global-version")
 
+// LocalVersionFileName The name of the file that pins the solc version for a project directory and its subdirectories
+const LocalVersionFileName = ".solc-version"
+
+// SolcVersionEnv The name of the environment variable that overrides the current version for a process
+const SolcVersionEnv = "SOLC_VERSION"
+
+// AutoInstallEnv The name of the environment variable that allows the solc wrapper to install missing versions
+const AutoInstallEnv = "GSOLC_SELECT_AUTO_INSTALL"
+
 // LinuxAmd64 The name of the operating system for generating a link to the repository with solc compilers for Linux
 const LinuxAmd64 = "linux-amd64"
 
@@ -57,8 +96,106 @@ const OldSolcUrl = "https://raw.githubusercontent.com/crytic/solc/master/linux/a
 // OldSolcListUrl Url to list of available old Solidity Compilers for Linux platform
 const OldSolcListUrl = "https://raw.githubusercontent.com/crytic/solc/new-list-json/linux/amd64/list.json"
 
+// SolcMirrorsEnv The name of the environment variable with comma separated mirrors of SoliditylangUrl
+const SolcMirrorsEnv = "GSOLC_SELECT_MIRRORS"
+
+// OldSolcMirrorsEnv The name of the environment variable with comma separated mirrors of OldSolcUrl
+const OldSolcMirrorsEnv = "GSOLC_SELECT_OLD_MIRRORS"
+
+// OldSolcListMirrorsEnv The name of the environment variable with comma separated mirrors of OldSolcListUrl
+const OldSolcListMirrorsEnv = "GSOLC_SELECT_OLD_LIST_MIRRORS"
+
+// SolcMirrors Base urls of repositories with builds of the Solidity Compiler, tried in order
+var SolcMirrors = mirrors(SolcMirrorsEnv, SoliditylangUrl)
+
+// OldSolcMirrors Initial parts of urls to the old Solidity Compilers for Linux platform, tried in order
+var OldSolcMirrors = mirrors(OldSolcMirrorsEnv, OldSolcUrl)
+
+// OldSolcListMirrors Urls to lists of available old Solidity Compilers for Linux platform, tried in order
+var OldSolcListMirrors = mirrors(OldSolcListMirrorsEnv, OldSolcListUrl)
+
+// HttpTimeout Timeout of connecting to a server, receiving response headers and waiting for the next chunk of data
+var HttpTimeout = 30 * time.Second
+
+// HttpRetries Number of retries of a request failed due to a server or connection error
+var HttpRetries = 3
+
+// HttpRetryBackoff Delay before the first retry of a failed request, doubled for each next retry
+var HttpRetryBackoff = time.Second
+
+// HttpConfigEnv The name of the environment variable that overrides HttpConfigPath
+const HttpConfigEnv = "GSOLC_SELECT_HTTP_CONFIG"
+
+// CaBundleEnv The name of the environment variable with the path to a PEM bundle of additional trusted CAs
+const CaBundleEnv = "GSOLC_SELECT_CA_BUNDLE"
+
+// ClientCertEnv The name of the environment variable with the path to a PEM client certificate
+const ClientCertEnv = "GSOLC_SELECT_CLIENT_CERT"
+
+// ClientKeyEnv The name of the environment variable with the path to a PEM key of the client certificate
+const ClientKeyEnv = "GSOLC_SELECT_CLIENT_KEY"
+
+// NetrcEnv The name of the environment variable with the path to a netrc file, as used by curl
+const NetrcEnv = "NETRC"
+
+// HttpConfigPath Path to the JSON file with headers sent to repositories and their mirrors, the file is optional
+var HttpConfigPath = env(HttpConfigEnv, filepath.Join(SolcDir, "http.json"))
+
+// CaBundle Path to a PEM bundle of CAs trusted in addition to the system ones
+var CaBundle = os.Getenv(CaBundleEnv)
+
+// ClientCert Path to a PEM client certificate presented to servers requesting it
+var ClientCert = os.Getenv(ClientCertEnv)
+
+// ClientKey Path to a PEM key of ClientCert, may be empty if the key is stored in the certificate file
+var ClientKey = os.Getenv(ClientKeyEnv)
+
+// Netrc Path to a netrc file with credentials of servers, the file is optional
+var Netrc = env(NetrcEnv, filepath.Join(HomeDir, ".netrc"))
+
+// UserAgent The User-Agent header of all requests
+const UserAgent = "gsolc-select/" + GoSolcSelect
+
+// LockTimeout Time to wait for a lock held by another process, negative value means waiting indefinitely
+var LockTimeout = 5 * time.Minute
+
+// UnzipMaxFileSize Maximum decompressed size of a file extracted from an archive with a compiler
+var UnzipMaxFileSize int64 = 256 << 20
+
+// UnzipMaxTotalSize Maximum decompressed size of all files extracted from an archive with a compiler
+var UnzipMaxTotalSize int64 = 512 << 20
+
+// SelfTestTimeout Time the installed compiler is given to report its version during the post-install self-test
+const SelfTestTimeout = time.Minute
+
+// DefaultJobs Default number of compilers installed concurrently in the parallel mode
+const DefaultJobs = 4
+
 // GoSolcSelect The go-solc-select version
 const GoSolcSelect = "0.2.0"
 
+// mirrors Returns the urls from the environment variable, or the default url if the variable is empty
+func mirrors(env string, url string) []string {
+	var urls []string
+	for _, field := range strings.FieldsFunc(os.Getenv(env), func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		urls = append(urls, strings.TrimSuffix(field, "/"))
+	}
+
+	if len(urls) == 0 {
+		return []string{url}
+	}
+
+	return urls
+}
+
+// env Returns the value of the environment variable, or the default value if the variable is empty
+func env(name string, value string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+
+	return value
+}
+
 // ValidSemVer Regular expression for version
 var ValidSemVer, _ = regexp.Compile(`^[\d]+(\.[\d]+){1,2}$`)
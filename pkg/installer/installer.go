@@ -23,197 +23,347 @@ package installer
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
+	"github.com/fabelx/go-solc-select/internal/lock"
 	"github.com/fabelx/go-solc-select/internal/utils"
 	"github.com/fabelx/go-solc-select/pkg/config"
+	"github.com/fabelx/go-solc-select/pkg/manifest"
+	"github.com/fabelx/go-solc-select/pkg/trust"
 	ver "github.com/fabelx/go-solc-select/pkg/versions"
 	"os"
 	"path/filepath"
 	"reflect"
 	"runtime"
 	"sync"
+	"time"
 )
 
-// download Returns an error if downloading of the solc compiler fails
-func download(platform ver.Platform, build *utils.BuildData) error {
-	url := platform.GenerateBuildUrl(build)
-	data, err := utils.Get(url)
+// errInstalled The error returned by download when the version is already installed and isn't downloaded again
+var errInstalled = goerrors.New("already installed")
+
+// download Returns the url the solc compiler was downloaded from, the number of downloaded bytes
+// and an error if downloading of the solc compiler fails
+//
+// Mirrors are tried in order until one of them serves the compiler matching the checksums of its build.
+// The compiler is streamed to the downloads directory first, so an interrupted download can be resumed by the next installation.
+// Once its checksums are verified, the compiler is laid out in the staging directory and atomically moved to artifacts,
+// so artifacts never contain a partially installed compiler.
+// The version is locked during the installation, an installed version (e.g. by another process meanwhile) isn't downloaded
+// again unless the options require reinstallation, errInstalled is returned then
+func download(ctx context.Context, platform ver.Platform, build *utils.BuildData, options *Options) (string, int64, error) {
+	// Another process may be installing or removing the same version
+	l, err := lock.Version(ctx, build.Version)
 	if err != nil {
-		return err
+		return "", 0, err
+	}
+	defer l.Release()
+
+	// The version was installed before or by another process while waiting for the lock
+	if !options.reinstall() && ver.GetInstalled()[build.Version] != "" {
+		return "", 0, errInstalled
 	}
 
-	// Verifying checksum of files
-	err = utils.VerifyChecksum(build.Keccak256, build.Sha256, data)
+	err = os.MkdirAll(config.SolcDownloads, 0755)
 	if err != nil {
-		return err
+		return "", 0, err
+	}
+
+	path := filepath.Join(config.SolcDownloads, filepath.Base(build.Path))
+	var url string
+	var size int64
+	for _, url = range platform.GenerateBuildUrls(build) {
+		mirror := url
+		options.report(&Event{Version: build.Version, Stage: StageDownloading, Url: mirror})
+		size, err = utils.Download(ctx, mirror, path, build.Keccak256, build.Sha256, func(bytes int64, total int64) {
+			options.report(&Event{Version: build.Version, Stage: StageDownloading, Url: mirror, Bytes: bytes, Total: total})
+		})
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if err != nil {
+		return url, 0, err
 	}
+	defer os.Remove(path)
+
+	// Checksums are computed while downloading and have been verified by now
+	options.report(&Event{Version: build.Version, Stage: StageVerifying, Url: url, Bytes: size, Total: size})
+	options.report(&Event{Version: build.Version, Stage: StageExtracting, Url: url, Bytes: size, Total: size})
+	return url, size, stage(ctx, platform, build, url, path, size, options)
+}
 
+// stage Lays out the compiler downloaded from the url in the staging directory and moves it to artifacts
+//
+// If the options require a self-test, the compiler is run in the staging directory and isn't moved
+// to artifacts unless it reports the expected version
+func stage(ctx context.Context, platform ver.Platform, build *utils.BuildData, url string, path string, size int64, options *Options) error {
 	name := fmt.Sprintf("solc-%s", build.Version)
-	folder := filepath.Join(config.SolcArtifacts, name)
+	err := os.MkdirAll(config.SolcStaging, 0755)
+	if err != nil {
+		return err
+	}
+
+	staging, err := os.MkdirTemp(config.SolcStaging, name+"-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
 
 	// Old compiler versions (<0.7.2) for windows have a different file structure
+	filePath := filepath.Join(staging, name)
 	if reflect.TypeOf(platform) == reflect.TypeOf(&ver.WindowsPlatform{}) && utils.IsOldWindowsVersion(build.Version) {
-		err = utils.Unzip(folder, data)
+		file, err := os.Open(path)
 		if err != nil {
 			return err
 		}
+		defer file.Close()
 
-		// Rename bin file solc.exe -> solc-[version] (solc-0.0.0)
-		filePath := filepath.Join(folder, "solc.exe")
-		newFilePath := filepath.Join(folder, name)
-		err := os.Rename(filePath, newFilePath)
+		err = utils.Unzip(ctx, staging, file, size)
 		if err != nil {
 			return err
 		}
 
-		return nil
+		// Rename bin file solc.exe -> solc-[version] (solc-0.0.0)
+		err = os.Rename(filepath.Join(staging, "solc.exe"), filePath)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = os.Rename(path, filePath)
+		if err != nil {
+			return err
+		}
 	}
 
-	err = os.MkdirAll(folder, os.ModePerm)
+	err = os.Chmod(filePath, 0775)
 	if err != nil {
 		return err
 	}
 
-	file, err := os.OpenFile(filepath.Join(folder, name), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0775)
+	if options.selfTest() {
+		options.report(&Event{Version: build.Version, Stage: StageTesting, Url: url, Bytes: size, Total: size})
+		err = selfTest(ctx, build, filePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The manifest records the provenance of the compiler and allows verifying the installed files later
+	m, err := manifest.Create(staging, build, url, platform.GetName())
 	if err != nil {
 		return err
 	}
 
-	defer file.Close()
-
-	_, err = file.Write(data)
+	err = m.Write(staging)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if err = ctx.Err(); err != nil {
+		return err
+	}
 
+	return commit(staging, filepath.Join(config.SolcArtifacts, name))
 }
 
-// InstallSolc Returns nil if the installation completed successfully
-func InstallSolc(version string) error {
-	platform, err := ver.GetPlatform(runtime.GOOS)
-	if err != nil {
+// commit Moves the staged compiler folder to its place in artifacts
+//
+// A folder already in place (e.g. left by an older installation) is replaced, the folder is never observed partially written
+func commit(staging string, folder string) error {
+	err := os.Rename(staging, folder)
+	if err == nil {
+		return nil
+	}
+
+	if _, statErr := os.Stat(folder); statErr != nil {
 		return err
 	}
 
-	builds, err := platform.GetBuilds()
+	old := staging + ".old"
+	err = os.Rename(folder, old)
 	if err != nil {
 		return err
 	}
+	defer os.RemoveAll(old)
 
-	build, err := ver.GetBuild(builds, version)
+	err = os.Rename(staging, folder)
 	if err != nil {
+		// Restores the previous folder
+		os.Rename(old, folder)
 		return err
 	}
 
-	err = download(platform, build)
+	return nil
+}
+
+// CleanStaging Removes entries of the staging directory left over by crashed installations
+//
+// Entries younger than config.StagingTTL may belong to an installation in progress and are kept
+func CleanStaging() error {
+	entries, err := os.ReadDir(config.SolcStaging)
 	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
 		return err
 	}
 
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < config.StagingTTL {
+			continue
+		}
+
+		os.RemoveAll(filepath.Join(config.SolcStaging, entry.Name()))
+	}
+
 	return nil
 }
 
-// InstallSolcs performs sequentially installation of compilers
-// Returns slice of installed compiler versions, slice of NOT installed compiler versions and error
-// If the context was cancelled, stops the installation and removes the compilers installed during the installation
-func InstallSolcs(ctx context.Context, versions []string) ([]string, []string, error) {
-	platform, err := ver.GetPlatform(runtime.GOOS)
+// getBuilds Returns the builds of the platform, the checksums of builds seen for the first time are pinned
+func getBuilds(ctx context.Context, platform ver.Platform) ([]*utils.BuildData, error) {
+	builds, err := platform.GetBuilds(ctx)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	builds, err := platform.GetBuilds()
+	err = trust.Remember(ctx, platform.GetName(), builds)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	var installed []string
-	var notInstalled []string
+	return builds, nil
+}
 
-	for _, version := range versions {
-		select {
-		case <-ctx.Done():
-			utils.Clean(installed)
-			return nil, nil, ctx.Err()
-		default:
-			build, err := ver.GetBuild(builds, version)
-			if err != nil {
-				notInstalled = append(notInstalled, version)
-				continue
-			}
+// install Installs the version, the outcome is described by the returned result
+func install(ctx context.Context, platform ver.Platform, builds []*utils.BuildData, version string, options *Options) *Result {
+	start := time.Now()
+	result := &Result{Version: version}
+	defer func() {
+		result.Duration = time.Since(start)
+		if result.Err != nil {
+			options.report(&Event{Version: version, Stage: StageFailed, Url: result.Url, Err: result.Err})
+		} else {
+			options.report(&Event{Version: version, Stage: StageDone, Url: result.Url, Bytes: result.Bytes, Total: result.Bytes})
+		}
+	}()
 
-			err = download(platform, build)
-			if err != nil {
-				notInstalled = append(notInstalled, build.Version)
-				continue
-			}
+	options.report(&Event{Version: version, Stage: StageResolving})
+	build, err := ver.GetBuild(builds, version)
+	if err != nil {
+		result.Err = err
+		return result
+	}
 
-			installed = append(installed, build.Version)
-		}
+	result.Build = build
+	// A repository or mirror serving a build with changed checksums may have been tampered with
+	err = trust.Check(ctx, platform.GetName(), build)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Url, result.Bytes, result.Err = download(ctx, platform, build, options)
+	if result.Err == errInstalled {
+		result.Skipped, result.Err = true, nil
 	}
 
-	return installed, notInstalled, nil
+	return result
 }
 
-// AsyncInstallSolcs performs asynchronously installation of compilers
-// Returns slice of installed compiler versions, slice of NOT installed compiler versions and error
-// If the context was cancelled, stops the installation and removes the compilers installed during the installation
-func AsyncInstallSolcs(ctx context.Context, versions []string) ([]string, []string, error) {
+// InstallSolc Returns the result of the installation and nil if the installation completed successfully
+func InstallSolc(ctx context.Context, version string, options *Options) (*Result, error) {
 	platform, err := ver.GetPlatform(runtime.GOOS)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+
+	builds, err := getBuilds(ctx, platform)
+	if err != nil {
+		return nil, err
 	}
 
-	builds, err := platform.GetBuilds()
+	result := install(ctx, platform, builds, version, options)
+	return result, result.Err
+}
+
+// InstallSolcs performs sequentially installation of compilers
+// Returns the results of the installation of each version in the passed order and error
+// If the context was cancelled, aborts the current download and returns the results so far with the context error,
+// the compilers installed before are kept
+func InstallSolcs(ctx context.Context, versions []string, options *Options) ([]*Result, error) {
+	platform, err := ver.GetPlatform(runtime.GOOS)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	var installed []string
-	var notInstalled []string
-	var buildsToInstall []*utils.BuildData
+	builds, err := getBuilds(ctx, platform)
+	if err != nil {
+		return nil, err
+	}
 
+	var results []*Result
 	for _, version := range versions {
-		build, err := ver.GetBuild(builds, version)
-		if err != nil {
-			notInstalled = append(notInstalled, version)
-			continue
+		results = append(results, install(ctx, platform, builds, version, options))
+		if ctx.Err() != nil {
+			return results, ctx.Err()
 		}
+	}
+
+	return results, nil
+}
 
-		buildsToInstall = append(buildsToInstall, build)
+// AsyncInstallSolcs performs asynchronously installation of compilers, at most `options.Jobs` compilers are installed at once
+// Returns the results of the installation of each version in the passed order and error
+// If the context was cancelled, aborts the downloads, waits for them to stop and returns the results with the context error,
+// the results of versions which weren't started are nil, the compilers installed before are kept
+func AsyncInstallSolcs(ctx context.Context, versions []string, options *Options) ([]*Result, error) {
+	platform, err := ver.GetPlatform(runtime.GOOS)
+	if err != nil {
+		return nil, err
+	}
+
+	builds, err := getBuilds(ctx, platform)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := options.jobs()
+	// Each worker writes only the results of the versions it took, so no synchronization is needed
+	results := make([]*Result, len(versions))
+	queue := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < jobs && i < len(versions); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range queue {
+				results[index] = install(ctx, platform, builds, versions[index], options)
+			}
+		}()
 	}
 
 	// Install solc compilers
-	cn := make(chan bool, 1)
-	go func() {
-		wg := sync.WaitGroup{}
-		for _, build := range buildsToInstall {
-			wg.Add(1)
-			build := build
-			go func() {
-				defer wg.Done()
-				err := download(platform, build)
-				if err != nil {
-					notInstalled = append(notInstalled, build.Version)
-					return
-				}
-
-				installed = append(installed, build.Version)
-			}()
+enqueue:
+	for index := range versions {
+		select {
+		case queue <- index:
+		case <-ctx.Done():
+			break enqueue
 		}
+	}
 
-		wg.Wait()
-		cn <- true
-	}()
+	// Downloads are aborted by the context, so the workers stop promptly after cancellation
+	close(queue)
+	wg.Wait()
 
-	select {
-	case <-ctx.Done():
-		utils.Clean(installed)
-		return nil, nil, ctx.Err()
-	case <-cn:
+	// A compiler appears in artifacts only once complete, so there is nothing to clean up
+	if ctx.Err() != nil {
+		return results, ctx.Err()
 	}
 
-	return installed, notInstalled, nil
+	return results, nil
 }
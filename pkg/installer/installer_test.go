@@ -2,23 +2,30 @@ package installer
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"github.com/fabelx/go-solc-select/internal/errors"
 	"github.com/fabelx/go-solc-select/internal/utils"
 	"github.com/fabelx/go-solc-select/pkg/config"
+	"github.com/fabelx/go-solc-select/pkg/manifest"
+	"github.com/fabelx/go-solc-select/pkg/trust"
+	ver "github.com/fabelx/go-solc-select/pkg/versions"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/sha3"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path"
 	"path/filepath"
+	"runtime"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestMain(m *testing.M) {
-
-	if os.Getenv("CI") != "" {
-		return
-	}
-
 	err := setup()
 	if err != nil {
 		log.Fatalf("Failed to run tests during setup. Error: %v", err)
@@ -34,6 +41,11 @@ func setup() error {
 	// creates dirs for testing
 	config.SolcDir = filepath.Join(config.HomeDir, ".test-gsolc-select")
 	config.SolcArtifacts = filepath.Join(config.SolcDir, "artifacts")
+	config.SolcLocks = filepath.Join(config.SolcDir, "locks")
+	config.SolcCache = filepath.Join(config.SolcDir, "cache")
+	config.KnownHashesPath = filepath.Join(config.SolcDir, "known-hashes.json")
+	config.SolcDownloads = filepath.Join(config.SolcDir, "downloads")
+	config.SolcStaging = filepath.Join(config.SolcDir, "staging")
 	err := os.MkdirAll(config.SolcArtifacts, 0755)
 	if err != nil {
 		return err
@@ -47,7 +59,113 @@ func shutdown() {
 	os.RemoveAll(config.SolcDir)
 }
 
+// skipInCI Skips tests downloading compilers from the real repository
+func skipInCI(t *testing.T) {
+	if os.Getenv("CI") != "" {
+		t.Skip("downloads compilers from the real repository")
+	}
+}
+
+// registry Fake repository of solc compilers
+type registry struct {
+	*httptest.Server
+	active    int32
+	maxActive int32
+}
+
+// newRegistry Starts a fake repository serving the passed versions, the repository is used until the test ends
+//
+// The compilers of broken versions don't match their checksums
+func newRegistry(t *testing.T, versions []string, broken []string) *registry {
+	return newRegistryOf(t, versions, broken, func(version string) []byte {
+		return []byte(fmt.Sprintf("solc %s", version))
+	})
+}
+
+// newRegistryOf Starts a fake repository serving the versions with the content returned by the function
+func newRegistryOf(t *testing.T, versions []string, broken []string, content func(version string) []byte) *registry {
+	platform, _ := ver.GetPlatform(runtime.GOOS)
+	name := platform.GetName()
+	files := make(map[string][]byte)
+	list := utils.ResponseData{Releases: map[string]string{}}
+	for _, version := range versions {
+		path := fmt.Sprintf("solc-%s-v%s+commit.00000000", name, version)
+		data := content(version)
+		files[path] = data
+		list.Releases[version] = path
+
+		k := sha3.NewLegacyKeccak256()
+		k.Write(data)
+		build := &utils.BuildData{
+			Path:      path,
+			Version:   version,
+			Keccak256: fmt.Sprintf("0x%x", k.Sum(nil)),
+			Sha256:    fmt.Sprintf("0x%x", sha256.Sum256(data)),
+		}
+		for _, b := range broken {
+			if b == version {
+				build.Sha256 = "0x00"
+			}
+		}
+
+		list.Builds = append(list.Builds, build)
+	}
+
+	r := &registry{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/old/list.json":
+			json.NewEncoder(w).Encode(utils.ResponseData{})
+		case fmt.Sprintf("/%s/list.json", name):
+			json.NewEncoder(w).Encode(list)
+		default:
+			data, ok := files[path.Base(req.URL.Path)]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			active := atomic.AddInt32(&r.active, 1)
+			defer atomic.AddInt32(&r.active, -1)
+			for {
+				max := atomic.LoadInt32(&r.maxActive)
+				if active <= max || atomic.CompareAndSwapInt32(&r.maxActive, max, active) {
+					break
+				}
+			}
+
+			time.Sleep(10 * time.Millisecond)
+			w.Write(data)
+		}
+	}))
+
+	solcMirrors, oldSolcListMirrors := config.SolcMirrors, config.OldSolcListMirrors
+	config.SolcMirrors = []string{r.URL}
+	config.OldSolcListMirrors = []string{r.URL + "/old/list.json"}
+	t.Cleanup(func() {
+		r.Close()
+		config.SolcMirrors, config.OldSolcListMirrors = solcMirrors, oldSolcListMirrors
+		os.Remove(config.KnownHashesPath)
+		for _, version := range versions {
+			os.RemoveAll(filepath.Join(config.SolcArtifacts, fmt.Sprintf("solc-%s", version)))
+		}
+	})
+
+	return r
+}
+
+// versionsOf Returns the versions of the results
+func versionsOf(results []*Result) []string {
+	var versions []string
+	for _, result := range results {
+		versions = append(versions, result.Version)
+	}
+
+	return versions
+}
+
 func TestInstallSolc(t *testing.T) {
+	skipInCI(t)
 	testCases := []struct {
 		name     string
 		input    *utils.BuildData
@@ -77,7 +195,7 @@ func TestInstallSolc(t *testing.T) {
 
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			err := InstallSolc(testCase.input.Version)
+			_, err := InstallSolc(context.Background(), testCase.input.Version, nil)
 			if err == nil {
 				name := fmt.Sprintf("solc-%s", testCase.input.Version)
 				assert.FileExists(t, filepath.Join(config.SolcArtifacts, name, name))
@@ -89,6 +207,7 @@ func TestInstallSolc(t *testing.T) {
 }
 
 func TestInstallSolcs(t *testing.T) {
+	skipInCI(t)
 	testCases := []struct {
 		input                []string
 		expectedInstalled    []string
@@ -107,8 +226,14 @@ func TestInstallSolcs(t *testing.T) {
 	}
 
 	for _, testCase := range testCases {
-		resultInstalled, resultNotInstalled, err := InstallSolcs(context.Background(), testCase.input)
+		// installed versions would be skipped
+		for _, version := range testCase.input {
+			os.RemoveAll(filepath.Join(config.SolcArtifacts, fmt.Sprintf("solc-%s", version)))
+		}
+
+		results, err := InstallSolcs(context.Background(), testCase.input, nil)
 		assert.NoError(t, err)
+		resultInstalled, resultNotInstalled := Installed(results), versionsOf(Failed(results))
 		for _, installed := range resultInstalled {
 			name := fmt.Sprintf("solc-%s", installed)
 			assert.FileExists(t, filepath.Join(config.SolcArtifacts, name, name))
@@ -119,6 +244,7 @@ func TestInstallSolcs(t *testing.T) {
 }
 
 func TestAsyncInstallSolcs(t *testing.T) {
+	skipInCI(t)
 	testCases := []struct {
 		input                []string
 		expectedInstalled    []string
@@ -137,8 +263,14 @@ func TestAsyncInstallSolcs(t *testing.T) {
 	}
 
 	for _, testCase := range testCases {
-		resultInstalled, resultNotInstalled, err := AsyncInstallSolcs(context.Background(), testCase.input)
+		// installed versions would be skipped
+		for _, version := range testCase.input {
+			os.RemoveAll(filepath.Join(config.SolcArtifacts, fmt.Sprintf("solc-%s", version)))
+		}
+
+		results, err := AsyncInstallSolcs(context.Background(), testCase.input, nil)
 		assert.NoError(t, err)
+		resultInstalled, resultNotInstalled := Installed(results), versionsOf(Failed(results))
 		for _, installed := range resultInstalled {
 			name := fmt.Sprintf("solc-%s", installed)
 			assert.FileExists(t, filepath.Join(config.SolcArtifacts, name, name))
@@ -147,3 +279,310 @@ func TestAsyncInstallSolcs(t *testing.T) {
 		assert.ElementsMatch(t, testCase.expectedNotInstalled, resultNotInstalled)
 	}
 }
+
+func TestAsyncInstallSolcsWorkerPool(t *testing.T) {
+	var versions []string
+	for i := 0; i < 20; i++ {
+		versions = append(versions, fmt.Sprintf("0.8.%d", i))
+	}
+
+	r := newRegistry(t, versions, []string{"0.8.3", "0.8.11"})
+	input := append([]string{"0.0.0"}, versions...)
+	results, err := AsyncInstallSolcs(context.Background(), input, &Options{Jobs: 3})
+	assert.NoError(t, err)
+	installed, notInstalled := Installed(results), versionsOf(Failed(results))
+
+	var expectedInstalled []string
+	for _, version := range versions {
+		if version != "0.8.3" && version != "0.8.11" {
+			expectedInstalled = append(expectedInstalled, version)
+		}
+	}
+
+	// Results are ordered as the versions were passed
+	assert.Equal(t, expectedInstalled, installed)
+	assert.Equal(t, []string{"0.0.0", "0.8.3", "0.8.11"}, notInstalled)
+	assert.LessOrEqual(t, r.maxActive, int32(3))
+
+	// Results describe why a version failed
+	assert.Equal(t, &errors.UnknownVersionError{Version: "0.0.0"}, results[0].Err)
+	assert.Equal(t, &errors.ChecksumMismatchError{HashFunc: "Sha256", Platform: runtime.GOOS}, results[4].Err)
+	assert.Equal(t, "0.8.0", results[1].Build.Version)
+	assert.Equal(t, int64(len("solc 0.8.0")), results[1].Bytes)
+	assert.Equal(t, r.URL, results[1].Url[:len(r.URL)])
+	for _, version := range installed {
+		name := fmt.Sprintf("solc-%s", version)
+		assert.FileExists(t, filepath.Join(config.SolcArtifacts, name, name))
+	}
+}
+
+func TestAsyncInstallSolcsCancel(t *testing.T) {
+	versions := []string{"0.8.0", "0.8.1", "0.8.2", "0.8.3"}
+	newRegistry(t, versions, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := AsyncInstallSolcs(ctx, versions, &Options{Jobs: 2})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, Installed(results))
+	for _, version := range versions {
+		assert.NoDirExists(t, filepath.Join(config.SolcArtifacts, fmt.Sprintf("solc-%s", version)))
+	}
+}
+
+func TestInstallSolcsCancelKeepsInstalled(t *testing.T) {
+	newRegistry(t, []string{"0.8.0", "0.8.1"}, nil)
+	for _, install := range []func(context.Context, []string, *Options) ([]*Result, error){InstallSolcs, AsyncInstallSolcs} {
+		os.RemoveAll(filepath.Join(config.SolcArtifacts, "solc-0.8.0"))
+		ctx, cancel := context.WithCancel(context.Background())
+		results, err := install(ctx, []string{"0.8.0", "0.8.1"}, &Options{Jobs: 1, Progress: func(event *Event) {
+			if event.Version == "0.8.1" && event.Stage == StageDownloading {
+				cancel()
+			}
+		}})
+		cancel()
+		assert.ErrorIs(t, err, context.Canceled)
+
+		// the results tell which compilers were committed before the cancellation, they are complete and stay
+		assert.Len(t, results, 2)
+		assert.Equal(t, []string{"0.8.0"}, Installed(results))
+		assert.ErrorIs(t, results[1].Err, context.Canceled)
+		assert.FileExists(t, filepath.Join(config.SolcArtifacts, "solc-0.8.0", "solc-0.8.0"))
+		assert.NoDirExists(t, filepath.Join(config.SolcArtifacts, "solc-0.8.1"))
+	}
+}
+
+func TestInstallSolcsSkipsInstalled(t *testing.T) {
+	ctx := context.Background()
+	newRegistry(t, []string{"0.8.0", "0.8.1"}, nil)
+	_, err := InstallSolc(ctx, "0.8.0", nil)
+	assert.NoError(t, err)
+
+	// only the versions installed by this installation are reported as installed
+	for _, install := range []func(context.Context, []string, *Options) ([]*Result, error){InstallSolcs, AsyncInstallSolcs} {
+		os.RemoveAll(filepath.Join(config.SolcArtifacts, "solc-0.8.1"))
+		results, err := install(ctx, []string{"0.8.0", "0.8.1"}, nil)
+		assert.NoError(t, err)
+		assert.True(t, results[0].Skipped)
+		assert.False(t, results[1].Skipped)
+		assert.Equal(t, []string{"0.8.1"}, Installed(results))
+		assert.Empty(t, Failed(results))
+	}
+}
+
+func TestInstallSolcReplacesIncompleteFolder(t *testing.T) {
+	newRegistry(t, []string{"0.8.0"}, []string{"0.8.1"})
+
+	// folder left by an older interrupted installation
+	folder := filepath.Join(config.SolcArtifacts, "solc-0.8.0")
+	os.MkdirAll(folder, 0755)
+	os.WriteFile(filepath.Join(folder, "garbage"), []byte(""), 0644)
+
+	_, err := InstallSolc(context.Background(), "0.8.0", nil)
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(folder, "solc-0.8.0"))
+	assert.NoFileExists(t, filepath.Join(folder, "garbage"))
+
+	entries, _ := os.ReadDir(config.SolcStaging)
+	assert.Empty(t, entries)
+}
+
+func TestInstallSolcReinstall(t *testing.T) {
+	ctx := context.Background()
+	newRegistry(t, []string{"0.8.0"}, nil)
+	folder := filepath.Join(config.SolcArtifacts, "solc-0.8.0")
+	installed, err := InstallSolc(ctx, "0.8.0", nil)
+	assert.NoError(t, err)
+
+	// the manifest records the provenance of the compiler
+	platform, _ := ver.GetPlatform(runtime.GOOS)
+	m, err := manifest.Read(folder)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.8.0", m.Version)
+	assert.Equal(t, installed.Build, m.Build)
+	assert.Equal(t, installed.Url, m.Url)
+	assert.Equal(t, platform.GetName(), m.Platform)
+	assert.Equal(t, config.GoSolcSelect, m.GsolcSelect)
+	assert.WithinDuration(t, time.Now(), m.InstalledAt, time.Minute)
+	assert.Contains(t, m.Files, "solc-0.8.0")
+	assert.NoError(t, m.Verify(folder))
+
+	// installed versions are skipped unless reinstallation is required
+	os.WriteFile(filepath.Join(folder, "solc-0.8.0"), []byte("tampered"), 0755)
+	result, err := InstallSolc(ctx, "0.8.0", nil)
+	assert.NoError(t, err)
+	assert.True(t, result.Skipped)
+	assert.Empty(t, result.Url)
+	assert.Error(t, m.Verify(folder))
+
+	result, err = InstallSolc(ctx, "0.8.0", &Options{Reinstall: true})
+	assert.NoError(t, err)
+	assert.False(t, result.Skipped)
+	assert.NotEmpty(t, result.Url)
+	assert.NoError(t, m.Verify(folder))
+}
+
+func TestInstallSolcSelfTest(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake compilers are shell scripts")
+	}
+
+	ctx := context.Background()
+	options := &Options{SelfTest: true}
+	testCases := []struct {
+		name   string
+		output string
+		err    error
+	}{
+		{
+			name:   "test compiler reporting the expected version",
+			output: "solc, the solidity compiler commandline interface\nVersion: 0.8.0+commit.00000000.Linux.g++",
+		},
+		{
+			name:   "test compiler reporting another version",
+			output: "Version: 0.7.6+commit.00000000.Linux.g++",
+			err:    &errors.SelfTestError{Version: "0.8.0", Reason: "the compiler reports version 0.7.6"},
+		},
+		{
+			name:   "test compiler reporting another commit",
+			output: "Version: 0.8.0+commit.11111111.Linux.g++",
+			err:    &errors.SelfTestError{Version: "0.8.0", Reason: "the compiler reports commit 11111111 instead of 00000000"},
+		},
+		{
+			name:   "test compiler reporting no version",
+			output: "usage: solc",
+			err:    &errors.SelfTestError{Version: "0.8.0", Reason: "unrecognized output 'usage: solc'"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			newRegistryOf(t, []string{"0.8.0"}, nil, func(version string) []byte {
+				return []byte(fmt.Sprintf("#!/bin/sh\nprintf '%s\\n'\n", testCase.output))
+			})
+
+			_, err := InstallSolc(ctx, "0.8.0", options)
+			assert.Equal(t, testCase.err, err)
+			if testCase.err != nil {
+				assert.NoDirExists(t, filepath.Join(config.SolcArtifacts, "solc-0.8.0"))
+			} else {
+				assert.FileExists(t, filepath.Join(config.SolcArtifacts, "solc-0.8.0", "solc-0.8.0"))
+			}
+		})
+	}
+
+	t.Run("test compiler which can't be executed", func(t *testing.T) {
+		newRegistry(t, []string{"0.8.0"}, nil)
+		result, err := InstallSolc(ctx, "0.8.0", options)
+		assert.IsType(t, &errors.SelfTestError{}, err)
+		assert.Contains(t, err.Error(), "failed to execute the compiler")
+		assert.Equal(t, err, result.Err)
+		assert.NoDirExists(t, filepath.Join(config.SolcArtifacts, "solc-0.8.0"))
+
+		// the compiler isn't run without the option
+		_, err = InstallSolc(ctx, "0.8.0", nil)
+		assert.NoError(t, err)
+	})
+}
+
+func TestInstallSolcFailureLeavesNoFolder(t *testing.T) {
+	newRegistry(t, []string{"0.8.1"}, []string{"0.8.1"})
+	result, err := InstallSolc(context.Background(), "0.8.1", nil)
+	assert.Equal(t, &errors.ChecksumMismatchError{HashFunc: "Sha256", Platform: runtime.GOOS}, err)
+	assert.Equal(t, err, result.Err)
+	assert.NoDirExists(t, filepath.Join(config.SolcArtifacts, "solc-0.8.1"))
+}
+
+func TestInstallSolcChangedChecksum(t *testing.T) {
+	ctx := context.Background()
+	platform, _ := ver.GetPlatform(runtime.GOOS)
+	newRegistry(t, []string{"0.8.0"}, nil)
+	result, err := InstallSolc(ctx, "0.8.0", nil)
+	assert.NoError(t, err)
+	os.RemoveAll(filepath.Join(config.SolcArtifacts, "solc-0.8.0"))
+
+	// the repository serves the version with another checksum since then
+	newRegistry(t, []string{"0.8.0"}, []string{"0.8.0"})
+	_, err = InstallSolc(ctx, "0.8.0", nil)
+	assert.Equal(t, &errors.ChecksumChangedError{
+		Version:  "0.8.0",
+		Platform: platform.GetName(),
+		HashFunc: "Sha256",
+		Known:    result.Build.Sha256,
+		Received: "0x00",
+	}, err)
+	assert.NoDirExists(t, filepath.Join(config.SolcArtifacts, "solc-0.8.0"))
+
+	// the checksums are checked against the binary once the version is reset
+	known, err := trust.Reset(ctx, platform.GetName(), "0.8.0")
+	assert.NoError(t, err)
+	assert.True(t, known)
+	_, err = InstallSolc(ctx, "0.8.0", nil)
+	assert.Equal(t, &errors.ChecksumMismatchError{HashFunc: "Sha256", Platform: runtime.GOOS}, err)
+}
+
+func TestCleanStaging(t *testing.T) {
+	stale := filepath.Join(config.SolcStaging, "solc-0.8.0-stale")
+	fresh := filepath.Join(config.SolcStaging, "solc-0.8.0-fresh")
+	os.MkdirAll(stale, 0755)
+	os.MkdirAll(fresh, 0755)
+	defer os.RemoveAll(fresh)
+	old := time.Now().Add(-2 * config.StagingTTL)
+	os.Chtimes(stale, old, old)
+
+	err := CleanStaging()
+	assert.NoError(t, err)
+	assert.NoDirExists(t, stale)
+	assert.DirExists(t, fresh)
+}
+
+func TestInstallSolcsProgress(t *testing.T) {
+	r := newRegistry(t, []string{"0.8.0", "0.8.1"}, []string{"0.8.1"})
+	var events []Event
+	_, err := InstallSolcs(context.Background(), []string{"0.8.0", "0.8.1"}, &Options{Progress: func(event *Event) {
+		events = append(events, *event)
+	}})
+	assert.NoError(t, err)
+
+	var stages []Stage
+	for _, event := range events {
+		if event.Version == "0.8.0" && (len(stages) == 0 || stages[len(stages)-1] != event.Stage) {
+			stages = append(stages, event.Stage)
+		}
+	}
+
+	assert.Equal(t, []Stage{StageResolving, StageDownloading, StageVerifying, StageExtracting, StageDone}, stages)
+	size := int64(len("solc 0.8.0"))
+	url := fmt.Sprintf("%s/%s", r.URL, "linux-amd64/solc-linux-amd64-v0.8.0+commit.00000000")
+	if runtime.GOOS == "linux" {
+		assert.Contains(t, events, Event{Version: "0.8.0", Stage: StageDownloading, Url: url, Bytes: size, Total: size})
+	}
+
+	last := events[len(events)-1]
+	assert.Equal(t, "0.8.1", last.Version)
+	assert.Equal(t, StageFailed, last.Stage)
+	assert.Equal(t, &errors.ChecksumMismatchError{HashFunc: "Sha256", Platform: runtime.GOOS}, last.Err)
+}
+
+func TestInstallSolcMirrorFailover(t *testing.T) {
+	r := newRegistry(t, []string{"0.8.0"}, nil)
+
+	// the mirror has no lists and serves corrupted compilers
+	var requests int32
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if path.Base(req.URL.Path) == "list.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte("corrupted"))
+	}))
+	defer broken.Close()
+	config.SolcMirrors = []string{broken.URL, r.URL}
+
+	result, err := InstallSolc(context.Background(), "0.8.0", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, r.URL, result.Url[:len(r.URL)])
+	assert.Equal(t, int32(2), requests)
+	assert.FileExists(t, filepath.Join(config.SolcArtifacts, "solc-0.8.0", "solc-0.8.0"))
+}
@@ -0,0 +1,98 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package installer
+
+import "github.com/fabelx/go-solc-select/pkg/config"
+
+// Stage Stage of the installation of a compiler version
+type Stage string
+
+const (
+	// StageResolving The build of the version is looked up in the list of builds
+	StageResolving Stage = "resolving"
+	// StageDownloading The compiler is being downloaded from Event.Url, see Event.Bytes and Event.Total
+	StageDownloading Stage = "downloading"
+	// StageVerifying The checksums of the downloaded compiler are being verified
+	StageVerifying Stage = "verifying"
+	// StageExtracting The compiler is being moved to artifacts
+	StageExtracting Stage = "extracting"
+	// StageTesting The installed compiler is being run to check it reports the expected version, see Options.SelfTest
+	StageTesting Stage = "testing"
+	// StageDone The compiler is installed
+	StageDone Stage = "done"
+	// StageFailed The installation failed, see Event.Err
+	StageFailed Stage = "failed"
+)
+
+// Event Progress of the installation of a compiler version
+type Event struct {
+	Version string `json:"version"`
+	Stage   Stage  `json:"stage"`
+	Url     string `json:"url,omitempty"`
+	Bytes   int64  `json:"bytes,omitempty"`
+	Total   int64  `json:"total,omitempty"`
+	Err     error  `json:"-"`
+}
+
+// Observer Receives progress events of installations
+//
+// Parallel installations report from several goroutines, so the observer must be safe for concurrent use
+type Observer func(event *Event)
+
+// Options Options of installations, nil options mean the defaults
+type Options struct {
+	// Jobs Maximum number of compilers installed at once by AsyncInstallSolcs, config.DefaultJobs if not positive
+	Jobs int
+	// Progress Observer receiving progress events, may be nil
+	Progress Observer
+	// Reinstall Replace installed versions instead of skipping them, e.g. to repair broken installations
+	Reinstall bool
+	// SelfTest Run `solc --version` of each installed compiler and fail the installation
+	// if the compiler can't be executed or reports another version
+	SelfTest bool
+}
+
+// jobs Returns the maximum number of compilers installed at once
+func (r *Options) jobs() int {
+	if r == nil || r.Jobs < 1 {
+		return config.DefaultJobs
+	}
+
+	return r.Jobs
+}
+
+// reinstall Reports whether installed versions are replaced
+func (r *Options) reinstall() bool {
+	return r != nil && r.Reinstall
+}
+
+// selfTest Reports whether installed compilers are self-tested
+func (r *Options) selfTest() bool {
+	return r != nil && r.SelfTest
+}
+
+// report Passes the event to the progress observer if there is one
+func (r *Options) report(event *Event) {
+	if r != nil && r.Progress != nil {
+		r.Progress(event)
+	}
+}
@@ -0,0 +1,65 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package installer
+
+import (
+	"github.com/fabelx/go-solc-select/internal/utils"
+	"time"
+)
+
+// Result Outcome of the installation of a compiler version
+//
+// Url is the mirror url the compiler was downloaded from, or the last tried one if the installation failed.
+// Skipped is set if the version was already installed and was left as is
+type Result struct {
+	Version  string           `json:"version"`
+	Build    *utils.BuildData `json:"build,omitempty"`
+	Url      string           `json:"url,omitempty"`
+	Bytes    int64            `json:"bytes"`
+	Duration time.Duration    `json:"duration"`
+	Skipped  bool             `json:"skipped,omitempty"`
+	Err      error            `json:"-"`
+}
+
+// Installed Returns the versions installed successfully by the installation, skipped versions aren't included
+func Installed(results []*Result) []string {
+	var versions []string
+	for _, result := range results {
+		if result != nil && result.Err == nil && !result.Skipped {
+			versions = append(versions, result.Version)
+		}
+	}
+
+	return versions
+}
+
+// Failed Returns the results of the versions which failed to install
+func Failed(results []*Result) []*Result {
+	var failed []*Result
+	for _, result := range results {
+		if result != nil && result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+
+	return failed
+}
@@ -0,0 +1,94 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package installer
+
+import (
+	"context"
+	"fmt"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/internal/utils"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// reportedVersion Matches the version and commit in the output of `solc --version`,
+// e.g. `Version: 0.8.17+commit.8df45f5f.Linux.g++`
+var reportedVersion = regexp.MustCompile(`Version: (\d+\.\d+\.\d+)[^+\s]*\+commit\.([0-9a-f]+)`)
+
+// buildCommit Matches the commit in the long version, path or name of a build
+var buildCommit = regexp.MustCompile(`commit\.([0-9a-f]+)`)
+
+// expectedCommit Returns the commit of the build, empty if the list doesn't mention it
+func expectedCommit(build *utils.BuildData) string {
+	for _, s := range []string{build.LongVersion, build.Build, build.Path, build.Name} {
+		if match := buildCommit.FindStringSubmatch(s); match != nil {
+			return match[1]
+		}
+	}
+
+	return ""
+}
+
+// selfTest Runs `solc --version` of the compiler at the path and checks it reports the version and commit of the build
+//
+// Catches compilers which pass the checksums but can't run on the machine, e.g. due to a missing loader,
+// a file system mounted with noexec or a binary built for another architecture
+func selfTest(ctx context.Context, build *utils.BuildData, path string) error {
+	ctx, cancel := context.WithTimeout(ctx, config.SelfTestTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, path, "--version").CombinedOutput()
+	if err != nil && ctx.Err() == context.Canceled {
+		return ctx.Err()
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return &errors.SelfTestError{Version: build.Version, Reason: fmt.Sprintf("the compiler didn't report its version within %s", config.SelfTestTimeout)}
+	}
+
+	if err != nil {
+		reason := fmt.Sprintf("failed to execute the compiler: %s", err)
+		if out := strings.TrimSpace(string(output)); out != "" {
+			reason = fmt.Sprintf("%s: %s", reason, out)
+		}
+
+		return &errors.SelfTestError{Version: build.Version, Reason: reason}
+	}
+
+	match := reportedVersion.FindStringSubmatch(string(output))
+	if match == nil {
+		return &errors.SelfTestError{Version: build.Version, Reason: fmt.Sprintf("unrecognized output '%s'", strings.TrimSpace(string(output)))}
+	}
+
+	if match[1] != build.Version {
+		return &errors.SelfTestError{Version: build.Version, Reason: fmt.Sprintf("the compiler reports version %s", match[1])}
+	}
+
+	// Commits are compared by the shortest prefix, lists and compilers may abbreviate them differently
+	if commit := expectedCommit(build); commit != "" && !strings.HasPrefix(commit, match[2]) && !strings.HasPrefix(match[2], commit) {
+		return &errors.SelfTestError{Version: build.Version, Reason: fmt.Sprintf("the compiler reports commit %s instead of %s", match[2], commit)}
+	}
+
+	return nil
+}
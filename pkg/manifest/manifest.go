@@ -0,0 +1,147 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package manifest
+
+import (
+	"encoding/json"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/internal/utils"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// FileHashes Checksums of an installed file
+type FileHashes struct {
+	Sha256    string `json:"sha256"`
+	Keccak256 string `json:"keccak256"`
+}
+
+// Manifest Description of an installed compiler version, stored in its folder in artifacts
+//
+// Besides the checksums of the installed files it records the provenance of the compiler: the build from the list,
+// the url it was downloaded from, the platform, the time of the installation and the gsolc-select version which installed it.
+// Files are keyed by the slash separated path relative to the folder
+type Manifest struct {
+	Version     string                 `json:"version"`
+	Build       *utils.BuildData       `json:"build,omitempty"`
+	Url         string                 `json:"url,omitempty"`
+	Platform    string                 `json:"platform,omitempty"`
+	InstalledAt time.Time              `json:"installed_at"`
+	GsolcSelect string                 `json:"gsolc_select,omitempty"`
+	Files       map[string]*FileHashes `json:"files"`
+}
+
+// Create Returns the manifest of the build laid out in the folder with checksums of all its files
+//
+// The url is the one the build was downloaded from, the platform is the name of the platform in the repository
+func Create(folder string, build *utils.BuildData, url string, platform string) (*Manifest, error) {
+	m := &Manifest{
+		Version:     build.Version,
+		Build:       build,
+		Url:         url,
+		Platform:    platform,
+		InstalledAt: time.Now().UTC(),
+		GsolcSelect: config.GoSolcSelect,
+		Files:       map[string]*FileHashes{},
+	}
+	err := filepath.WalkDir(folder, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		name, err := filepath.Rel(folder, path)
+		if err != nil || name == config.ManifestFileName {
+			return err
+		}
+
+		checksum, err := utils.HashFile(path)
+		if err != nil {
+			return err
+		}
+
+		m.Files[filepath.ToSlash(name)] = &FileHashes{Sha256: checksum.Sha256(), Keccak256: checksum.Keccak256()}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Read Returns the manifest stored in the folder, the error satisfies os.IsNotExist if there is no manifest
+func Read(folder string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(folder, config.ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{}
+	err = json.Unmarshal(data, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Write Stores the manifest in the folder
+func (m *Manifest) Write(folder string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(folder, config.ManifestFileName), data, 0644)
+}
+
+// Verify Returns an error if a file listed in the manifest is missing from the folder or its checksums differ
+//
+// The manifest may be rewritten along with the files, it doesn't prove the files are genuine on its own (see pkg/verifier)
+func (m *Manifest) Verify(folder string) error {
+	names := make([]string, 0, len(m.Files))
+	for name := range m.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		checksum, err := utils.HashFile(filepath.Join(folder, filepath.FromSlash(name)))
+		if err != nil {
+			return err
+		}
+
+		if checksum.Sha256() != m.Files[name].Sha256 {
+			return &errors.ModifiedFileError{Version: m.Version, File: name, HashFunc: "Sha256"}
+		}
+
+		if checksum.Keccak256() != m.Files[name].Keccak256 {
+			return &errors.ModifiedFileError{Version: m.Version, File: name, HashFunc: "Keccak256"}
+		}
+	}
+
+	return nil
+}
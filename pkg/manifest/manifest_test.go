@@ -0,0 +1,44 @@
+package manifest
+
+import (
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/internal/utils"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	// layout of an extracted old compiler version for windows
+	folder := t.TempDir()
+	os.MkdirAll(filepath.Join(folder, "lib"), 0755)
+	os.WriteFile(filepath.Join(folder, "solc-0.4.1"), []byte("solc"), 0755)
+	os.WriteFile(filepath.Join(folder, "lib", "msvcp140.dll"), []byte("dll"), 0644)
+
+	build := &utils.BuildData{Path: "solc-windows-amd64-v0.4.1+commit.4fc6fc2c.zip", Version: "0.4.1", LongVersion: "0.4.1+commit.4fc6fc2c"}
+	m, err := Create(folder, build, "https://binaries.soliditylang.org/windows-amd64/"+build.Path, config.WindowsAmd64)
+	assert.NoError(t, err)
+	assert.NoError(t, m.Write(folder))
+	assert.Equal(t, "0.4.1", m.Version)
+	assert.Equal(t, config.GoSolcSelect, m.GsolcSelect)
+	assert.False(t, m.InstalledAt.IsZero())
+
+	read, err := Read(folder)
+	assert.NoError(t, err)
+	assert.Equal(t, m, read)
+	assert.Len(t, read.Files, 2)
+	assert.Contains(t, read.Files, "lib/msvcp140.dll")
+	assert.NotContains(t, read.Files, config.ManifestFileName)
+	assert.NoError(t, read.Verify(folder))
+
+	os.WriteFile(filepath.Join(folder, "lib", "msvcp140.dll"), []byte("tampered"), 0644)
+	assert.Equal(t, &errors.ModifiedFileError{Version: "0.4.1", File: "lib/msvcp140.dll", HashFunc: "Sha256"}, read.Verify(folder))
+
+	os.Remove(filepath.Join(folder, "lib", "msvcp140.dll"))
+	assert.True(t, os.IsNotExist(read.Verify(folder)))
+
+	_, err = Read(t.TempDir())
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,404 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+// Package pragma implements parsing and matching of Solidity version pragmas
+// (`pragma solidity ^0.8.0;`) following the rules of the Solidity compiler.
+package pragma
+
+import (
+	"fmt"
+	"github.com/Masterminds/semver"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"math"
+	"strings"
+)
+
+// wildcard Stands for `x`, `X` and `*` version parts
+const wildcard = -1
+
+// endOfInput Returned by the parser when there are no more characters to read
+const endOfInput = -1
+
+type tokenKind int
+
+const (
+	literalToken tokenKind = iota
+	operatorToken
+	orToken
+	hyphenToken
+	illegalToken
+)
+
+type token struct {
+	kind    tokenKind
+	literal string
+	offset  int
+}
+
+type component struct {
+	prefix  string
+	numbers [3]int64
+	levels  int
+}
+
+// Expression Parsed version pragma, a disjunction (||) of conjunctions of version components
+type Expression struct {
+	raw    string
+	ranges [][]*component
+}
+
+// Parse Returns the expression parsed from the version pragma, e.g. `^0.8.0`, `>=0.6.0 <0.8.0`,
+// `0.4.24 || ^0.5.0`, `0.5.0 - 0.6.0` or `0.7.x`
+//
+// Returns InvalidPragmaError if the pragma is malformed
+func Parse(pragma string) (*Expression, error) {
+	tokens, err := tokenize(pragma)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{pragma: pragma, tokens: tokens}
+	ranges, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Expression{raw: strings.TrimSpace(pragma), ranges: ranges}, nil
+}
+
+// String Returns the version pragma the expression was parsed from
+func (r *Expression) String() string {
+	return r.raw
+}
+
+// Matches Reports whether the version satisfies the expression
+func (r *Expression) Matches(version *semver.Version) bool {
+	for _, conjunction := range r.ranges {
+		matches := true
+		for _, c := range conjunction {
+			if !c.matches(version) {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Filter Returns versions satisfying the expression, keeps the order of the passed versions
+func (r *Expression) Filter(versions []*semver.Version) []*semver.Version {
+	var result []*semver.Version
+	for _, version := range versions {
+		if r.Matches(version) {
+			result = append(result, version)
+		}
+	}
+
+	return result
+}
+
+// matches Reports whether the version satisfies the component
+func (r *component) matches(version *semver.Version) bool {
+	switch r.prefix {
+	case "~":
+		// ~1.2.3 is >=1.2.3 and <=1.2.x, ~1 is >=1 and <=1.x.x
+		comp := *r
+		comp.prefix = ">="
+		if !comp.matches(version) {
+			return false
+		}
+
+		if r.levels >= 2 {
+			comp.levels = 2
+		} else {
+			comp.levels = 1
+		}
+
+		comp.prefix = "<="
+		return comp.matches(version)
+	case "^":
+		// ^1.2.3 is >=1.2.3 and <=1.x.x, ^0.4.1 is >=0.4.1 and <=0.4.x
+		comp := *r
+		comp.prefix = ">="
+		if !comp.matches(version) {
+			return false
+		}
+
+		if r.numbers[0] == 0 && r.levels != 1 {
+			comp.levels = 2
+		} else {
+			comp.levels = 1
+		}
+
+		comp.prefix = "<="
+		return comp.matches(version)
+	}
+
+	numbers := [3]int64{version.Major(), version.Minor(), version.Patch()}
+	cmp := int64(0)
+	didCompare := false
+	for i := 0; i < r.levels && cmp == 0; i++ {
+		if r.numbers[i] != wildcard {
+			didCompare = true
+			cmp = numbers[i] - r.numbers[i]
+		}
+	}
+
+	// Prereleases are lower than the release with the same version
+	if cmp == 0 && version.Prerelease() != "" && didCompare {
+		cmp = -1
+	}
+
+	switch r.prefix {
+	case "=":
+		return cmp == 0
+	case "<":
+		return didCompare && cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return didCompare && cmp > 0
+	case ">=":
+		return cmp >= 0
+	default:
+		return false
+	}
+}
+
+// tokenize Splits the pragma into tokens the same way as the Solidity scanner does it
+func tokenize(pragma string) ([]*token, error) {
+	var tokens []*token
+	for i := 0; i < len(pragma); {
+		c := pragma[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(pragma[i+1:], c)
+			if end == -1 {
+				return nil, &errors.InvalidPragmaError{Pragma: pragma, Reason: "unterminated string"}
+			}
+
+			tokens = append(tokens, &token{kind: literalToken, literal: pragma[i+1 : i+1+end], offset: i + 1})
+			i += end + 2
+		case strings.HasPrefix(pragma[i:], "||"):
+			tokens = append(tokens, &token{kind: orToken, literal: "||", offset: i})
+			i += 2
+		case strings.HasPrefix(pragma[i:], "<=") || strings.HasPrefix(pragma[i:], ">="):
+			tokens = append(tokens, &token{kind: operatorToken, literal: pragma[i : i+2], offset: i})
+			i += 2
+		case strings.IndexByte("<>=^~", c) != -1:
+			tokens = append(tokens, &token{kind: operatorToken, literal: pragma[i : i+1], offset: i})
+			i++
+		case c == '-':
+			tokens = append(tokens, &token{kind: hyphenToken, literal: "-", offset: i})
+			i++
+		default:
+			start := i
+			for i < len(pragma) && strings.IndexByte(" \t\n\r\"'|<>=^~-", pragma[i]) == -1 {
+				i++
+			}
+
+			// A single `|` isn't an operator
+			if i == start {
+				i++
+			}
+
+			tokens = append(tokens, &token{kind: literalToken, literal: pragma[start:i], offset: start})
+		}
+	}
+
+	return tokens, nil
+}
+
+// parser Parses version pragma tokens, the current position is a character inside the current token
+type parser struct {
+	pragma    string
+	tokens    []*token
+	pos       int
+	posInside int
+}
+
+func (r *parser) parse() ([][]*component, error) {
+	var ranges [][]*component
+	for {
+		conjunction, err := r.parseRange()
+		if err != nil {
+			return nil, err
+		}
+
+		ranges = append(ranges, conjunction)
+		if r.pos >= len(r.tokens) {
+			break
+		}
+
+		if r.currentToken() != orToken {
+			return nil, r.unexpected()
+		}
+
+		r.nextToken()
+	}
+
+	return ranges, nil
+}
+
+// parseRange Parses either a hyphen range (component - component) or a conjunction (component component*)
+func (r *parser) parseRange() ([]*component, error) {
+	first, err := r.parseComponent()
+	if err != nil {
+		return nil, err
+	}
+
+	conjunction := []*component{first}
+	if r.currentToken() == hyphenToken {
+		first.prefix = ">="
+		r.nextToken()
+		last, err := r.parseComponent()
+		if err != nil {
+			return nil, err
+		}
+
+		last.prefix = "<="
+		return append(conjunction, last), nil
+	}
+
+	for r.currentToken() != orToken && r.currentToken() != illegalToken {
+		c, err := r.parseComponent()
+		if err != nil {
+			return nil, err
+		}
+
+		conjunction = append(conjunction, c)
+	}
+
+	return conjunction, nil
+}
+
+// parseComponent Parses an optional operator followed by a version with up to three parts
+func (r *parser) parseComponent() (*component, error) {
+	c := &component{prefix: "="}
+	if r.currentToken() == operatorToken {
+		c.prefix = r.tokens[r.pos].literal
+		r.nextToken()
+	}
+
+	for c.levels < 3 {
+		number, err := r.parseVersionPart()
+		if err != nil {
+			return nil, err
+		}
+
+		c.numbers[c.levels] = number
+		c.levels++
+		if r.currentChar() != '.' {
+			break
+		}
+
+		r.nextChar()
+	}
+
+	return c, nil
+}
+
+// parseVersionPart Parses a number or a wildcard, a number is terminated at the end of the token
+func (r *parser) parseVersionPart() (int64, error) {
+	startPos := r.pos
+	c := r.currentChar()
+	if c == endOfInput {
+		return 0, r.unexpected()
+	}
+
+	err := r.unexpected()
+	r.nextChar()
+	switch {
+	case c == 'x' || c == 'X' || c == '*':
+		return wildcard, nil
+	case c == '0':
+		return 0, nil
+	case '1' <= c && c <= '9':
+		v := int64(c - '0')
+		for r.pos == startPos && '0' <= r.currentChar() && r.currentChar() <= '9' {
+			v = v*10 + int64(r.currentChar()-'0')
+			if v >= math.MaxUint32 {
+				return 0, &errors.InvalidPragmaError{Pragma: r.pragma, Reason: "version number is too large"}
+			}
+
+			r.nextChar()
+		}
+
+		return v, nil
+	default:
+		return 0, err
+	}
+}
+
+func (r *parser) currentToken() tokenKind {
+	if r.pos < len(r.tokens) {
+		return r.tokens[r.pos].kind
+	}
+
+	return illegalToken
+}
+
+func (r *parser) nextToken() {
+	r.pos++
+	r.posInside = 0
+}
+
+func (r *parser) currentChar() int {
+	if r.pos >= len(r.tokens) || r.posInside >= len(r.tokens[r.pos].literal) {
+		return endOfInput
+	}
+
+	return int(r.tokens[r.pos].literal[r.posInside])
+}
+
+func (r *parser) nextChar() {
+	if r.pos < len(r.tokens) {
+		if r.posInside+1 >= len(r.tokens[r.pos].literal) {
+			r.nextToken()
+		} else {
+			r.posInside++
+		}
+	}
+}
+
+// unexpected Returns an error describing the character at the current position
+func (r *parser) unexpected() error {
+	if r.pos >= len(r.tokens) {
+		return &errors.InvalidPragmaError{Pragma: r.pragma, Reason: "unexpected end of pragma"}
+	}
+
+	t := r.tokens[r.pos]
+	if r.posInside >= len(t.literal) {
+		return &errors.InvalidPragmaError{Pragma: r.pragma, Reason: fmt.Sprintf("unexpected empty string at position %d", t.offset)}
+	}
+
+	return &errors.InvalidPragmaError{
+		Pragma: r.pragma,
+		Reason: fmt.Sprintf("unexpected '%c' at position %d", t.literal[r.posInside], t.offset+r.posInside),
+	}
+}
@@ -0,0 +1,210 @@
+package pragma
+
+import (
+	"github.com/Masterminds/semver"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// The test cases mirror the SemVerMatcher tests of the Solidity compiler
+
+func TestParsePositive(t *testing.T) {
+	testCases := []struct {
+		pragma  string
+		version string
+	}{
+		{"*", "1.2.3-foo"},
+		{"1.0.0 - 2.0.0", "1.2.3"},
+		{"1.0.0", "1.0.0"},
+		{">=*", "0.2.4"},
+		{"*", "1.2.3"},
+		{">=1.0.0", "1.0.0"},
+		{">=1.0.0", "1.0.1"},
+		{">=1.0.0", "1.1.0"},
+		{">1.0.0", "1.0.1"},
+		{">1.0.0", "1.1.0"},
+		{"<=2.0.0", "2.0.0"},
+		{"<=2.0.0", "1.9999.9999"},
+		{"<=2.0.0", "0.2.9"},
+		{"<2.0.0", "1.9999.9999"},
+		{"<2.0.0", "0.2.9"},
+		{">= 1.0.0", "1.0.0"},
+		{">=  1.0.0", "1.0.1"},
+		{">=   1.0.0", "1.1.0"},
+		{"> 1.0.0", "1.0.1"},
+		{">  1.0.0", "1.1.0"},
+		{"<=   2.0.0", "2.0.0"},
+		{"<= 2.0.0", "1.9999.9999"},
+		{"<=  2.0.0", "0.2.9"},
+		{"<    2.0.0", "1.9999.9999"},
+		{"<\t2.0.0", "0.2.9"},
+		{">=0.1.97", "0.1.97"},
+		{"0.1.20 || 1.2.4", "1.2.4"},
+		{">=0.2.3 || <0.0.1", "0.0.0"},
+		{">=0.2.3 || <0.0.1", "0.2.3"},
+		{">=0.2.3 || <0.0.1", "0.2.4"},
+		{"\"2.x.x\"", "2.1.3"},
+		{"1.2.x", "1.2.3"},
+		{"\"1.2.x\" || \"2.x\"", "2.1.3"},
+		{"\"1.2.x\" || \"2.x\"", "1.2.3"},
+		{"x", "1.2.3"},
+		{"2.*.*", "2.1.3"},
+		{"1.2.*", "1.2.3"},
+		{"1.2.* || 2.*", "2.1.3"},
+		{"1.2.* || 2.*", "1.2.3"},
+		{"2", "2.1.2"},
+		{"2.3", "2.3.1"},
+		{"~2.4", "2.4.0"},
+		{"~2.4", "2.4.5"},
+		{"~1", "1.2.3"},
+		{"~1.0", "1.0.2"},
+		{"<1", "1.0.0-beta"},
+		{"< 1", "1.0.0-beta"},
+		{"=0.7.x", "0.7.2"},
+		{">=0.7.x", "0.7.2"},
+		{"<=0.7.x", "0.7.2"},
+		{"~1.2.1 >=1.2.3", "1.2.3"},
+		{"~1.2.1 =1.2.3", "1.2.3"},
+		{"~1.2.1 1.2.3", "1.2.3"},
+		{"~1.2.1 >=1.2.3 1.2.3", "1.2.3"},
+		{"~1.2.1 1.2.3 >=1.2.3", "1.2.3"},
+		{">=\"1.2.1\" 1.2.3", "1.2.3"},
+		{"1.2.3 >=1.2.1", "1.2.3"},
+		{">=1.2 1.2.8", "1.2.8"},
+		{"^1.2.3", "1.8.1"},
+		{"^0.1.2", "0.1.2"},
+		{"^0.1", "0.1.2"},
+		{"^1.2", "1.4.2"},
+		{"<=1.2.3", "1.2.3-beta"},
+		{">1.2", "1.3.0-beta"},
+		{"<1.2.3", "1.2.3-beta"},
+		{"^1.2 ^1", "1.4.2"},
+		{"^0", "0.5.1"},
+		{"^0", "0.1.1"},
+		{"^0.4.24", "0.4.26"},
+		{">=0.6.0 <0.8.0", "0.7.6"},
+		{">=0.6.0<0.8.0", "0.6.12"},
+		{"0.5.0-0.6.0", "0.5.17"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.pragma+" matches "+testCase.version, func(t *testing.T) {
+			expression, err := Parse(testCase.pragma)
+			assert.NoError(t, err)
+			assert.True(t, expression.Matches(semver.MustParse(testCase.version)))
+		})
+	}
+}
+
+func TestParseNegative(t *testing.T) {
+	testCases := []struct {
+		pragma  string
+		version string
+	}{
+		{"1.0.0 - 2.0.0", "2.2.3"},
+		{"1.0.0", "1.0.1"},
+		{">=1.0.0", "0.0.0"},
+		{">=1.0.0", "0.0.1"},
+		{">=1.0.0", "0.1.0"},
+		{">1.0.0", "0.0.1"},
+		{">1.0.0", "0.1.0"},
+		{"<=2.0.0", "3.0.0"},
+		{"<=2.0.0", "2.9999.9999"},
+		{"<=2.0.0", "2.2.9"},
+		{"<2.0.0", "2.9999.9999"},
+		{"<2.0.0", "2.2.9"},
+		{">=0.1.97", "0.1.93"},
+		{"0.1.20 || 1.2.4", "1.2.3"},
+		{">=0.2.3 || <0.0.1", "0.0.3"},
+		{">=0.2.3 || <0.0.1", "0.2.2"},
+		{"\"2.x.x\"", "1.1.3"},
+		{"\"2.x.x\"", "3.1.3"},
+		{"1.2.x", "1.3.3"},
+		{"\"1.2.x\" || \"2.x\"", "3.1.3"},
+		{"\"1.2.x\" || \"2.x\"", "1.1.3"},
+		{"2.*.*", "1.1.3"},
+		{"2.*.*", "3.1.3"},
+		{"1.2.*", "1.3.3"},
+		{"1.2.* || 2.*", "3.1.3"},
+		{"1.2.* || 2.*", "1.1.3"},
+		{"2", "1.1.2"},
+		{"2.3", "2.4.1"},
+		{"~2.4", "2.5.0"},
+		{"~2.4", "2.3.9"},
+		{"~1", "0.2.3"},
+		{"~1.0", "1.1.0"},
+		{"<1", "1.0.0"},
+		{">=1.2", "1.1.1"},
+		{"=0.7.x", "0.8.2"},
+		{"=1.2.3", "1.2.3-beta"},
+		{">1.2", "1.2.8"},
+		{"^1.2.3", "2.0.0-alpha"},
+		{"^1.2.3", "1.2.2"},
+		{"^1.2", "1.1.9"},
+		{"^0.4.24", "0.5.0"},
+		{"^0.0.1", "0.1.0"},
+		{">=0.6.0 <0.8.0", "0.8.0"},
+		{"<1.2.3", "1.2.3"},
+		{">=1.0.0 <1.0.0", "1.0.0"},
+		{"1.2.3.4", "1.2.3"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.pragma+" does not match "+testCase.version, func(t *testing.T) {
+			expression, err := Parse(testCase.pragma)
+			assert.NoError(t, err)
+			assert.False(t, expression.Matches(semver.MustParse(testCase.version)))
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	testCases := []struct {
+		pragma string
+		reason string
+	}{
+		{"", "unexpected end of pragma"},
+		{"abc", "unexpected 'a' at position 0"},
+		{">>1", "unexpected '>' at position 1"},
+		{"^^1", "unexpected '^' at position 1"},
+		{"1.2.", "unexpected end of pragma"},
+		{"1..2", "unexpected '.' at position 2"},
+		{"1.0.0 -", "unexpected end of pragma"},
+		{"1.0.0 - 2.0.0 - 3.0.0", "unexpected '-' at position 14"},
+		{"1.0.0 ||", "unexpected end of pragma"},
+		{"|| 1.0.0", "unexpected '|' at position 0"},
+		{"1.0.0 | 2.0.0", "unexpected '|' at position 6"},
+		{"0.8.0abc", "unexpected 'a' at position 5"},
+		{"\"\"", "unexpected empty string at position 1"},
+		{"\"0.8.0", "unterminated string"},
+		{"99999999999", "version number is too large"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.pragma, func(t *testing.T) {
+			expression, err := Parse(testCase.pragma)
+			assert.Nil(t, expression)
+			assert.Equal(t, &errors.InvalidPragmaError{Pragma: testCase.pragma, Reason: testCase.reason}, err)
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	versions := []*semver.Version{
+		semver.MustParse("0.4.24"),
+		semver.MustParse("0.4.26"),
+		semver.MustParse("0.5.0"),
+		semver.MustParse("0.7.6"),
+		semver.MustParse("0.8.19"),
+	}
+	expected := []*semver.Version{
+		semver.MustParse("0.4.26"),
+		semver.MustParse("0.7.6"),
+	}
+
+	expression, err := Parse(">0.4.24 <0.5.0 || ~0.7")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, expression.Filter(versions))
+	assert.Equal(t, ">0.4.24 <0.5.0 || ~0.7", expression.String())
+}
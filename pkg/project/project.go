@@ -0,0 +1,299 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+// Package project inspects Solidity source trees to determine their compiler requirements.
+package project
+
+import (
+	"github.com/Masterminds/semver"
+	"github.com/fabelx/go-solc-select/pkg/pragma"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// SolidityExt The extension of Solidity source files
+const SolidityExt = ".sol"
+
+// pragmaRegex Regular expression for version pragma directive
+var pragmaRegex = regexp.MustCompile(`\bpragma\s+solidity\s+([^;]*);`)
+
+// importRegex Regular expression for import directive
+var importRegex = regexp.MustCompile(`\bimport\b[^;]*;`)
+
+// importPathRegex Regular expression for the path of import directive
+var importPathRegex = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+
+// File Solidity source file and its compiler requirements
+type File struct {
+	Path    string   `json:"path"`
+	Pragmas []string `json:"pragmas"`
+	Imports []string `json:"imports,omitempty"`
+	Error   string   `json:"error,omitempty"`
+
+	expressions []*pragma.Expression
+}
+
+// Report Compiler requirements of Solidity source files
+type Report struct {
+	Files []*File `json:"files"`
+	// Versions satisfying constraints of every file
+	Versions []string `json:"versions"`
+	// Newest The newest version satisfying constraints of every file
+	Newest string `json:"newest"`
+	// Unsatisfiable Files whose constraints are satisfied by none of the versions
+	Unsatisfiable []string `json:"unsatisfiable"`
+	// Conflicts Pairs of files whose constraints are mutually incompatible
+	Conflicts [][2]string `json:"conflicts"`
+}
+
+// ParseFile Returns the source file with version pragmas extracted from it
+//
+// An invalid version pragma doesn't cause an error, it is reported in the Error field
+func ParseFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &File{Path: path, Pragmas: FindPragmas(data), Imports: FindImports(data)}
+	for _, p := range file.Pragmas {
+		expression, err := pragma.Parse(p)
+		if err != nil {
+			file.Error = err.Error()
+			file.expressions = nil
+			break
+		}
+
+		file.expressions = append(file.expressions, expression)
+	}
+
+	return file, nil
+}
+
+// Matches Reports whether the version satisfies all version pragmas of the file
+//
+// Always false for a file with an invalid version pragma
+func (r *File) Matches(version *semver.Version) bool {
+	if r.Error != "" {
+		return false
+	}
+
+	for _, expression := range r.expressions {
+		if !expression.Matches(version) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Scan Returns Solidity source files found in the directory and its subdirectories
+//
+// Hidden directories and `node_modules` are skipped, paths of files are relative to the directory
+func Scan(dir string) ([]*File, error) {
+	var files []*File
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			name := info.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || name == "node_modules") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if filepath.Ext(path) != SolidityExt {
+			return nil
+		}
+
+		file, err := ParseFile(path)
+		if err != nil {
+			return err
+		}
+
+		if rel, err := filepath.Rel(dir, path); err == nil {
+			file.Path = rel
+		}
+
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// NewReport Returns compiler requirements of the files checked against the passed versions
+//
+// Versions are expected to be sorted in ascending order (see versions.SortVersions)
+func NewReport(files []*File, versions []*semver.Version) *Report {
+	report := &Report{
+		Files:         files,
+		Versions:      []string{},
+		Unsatisfiable: []string{},
+		Conflicts:     [][2]string{},
+	}
+
+	// matches[i][j] reports whether i-th file is satisfied by j-th version
+	matches := make([][]bool, len(files))
+	for i, file := range files {
+		matches[i] = make([]bool, len(versions))
+		satisfiable := false
+		for j, version := range versions {
+			matches[i][j] = file.Matches(version)
+			satisfiable = satisfiable || matches[i][j]
+		}
+
+		if !satisfiable {
+			report.Unsatisfiable = append(report.Unsatisfiable, file.Path)
+		}
+	}
+
+	for j, version := range versions {
+		all := true
+		for i := range files {
+			if !matches[i][j] {
+				all = false
+				break
+			}
+		}
+
+		if all {
+			report.Versions = append(report.Versions, version.Original())
+			report.Newest = version.Original()
+		}
+	}
+
+	for i := range files {
+		for k := i + 1; k < len(files); k++ {
+			if !intersects(matches[i], matches[k]) && !isEmpty(matches[i]) && !isEmpty(matches[k]) {
+				report.Conflicts = append(report.Conflicts, [2]string{files[i].Path, files[k].Path})
+			}
+		}
+	}
+
+	return report
+}
+
+// FindPragmas Returns version pragmas (`pragma solidity <pragma>;`) of the Solidity source
+func FindPragmas(src []byte) []string {
+	pragmas := []string{}
+	code := scrub(src, false)
+	// Matches are searched in the code without string literals, but taken from the code with them
+	masked := scrub(src, true)
+	for _, match := range pragmaRegex.FindAllSubmatchIndex(masked, -1) {
+		pragmas = append(pragmas, strings.TrimSpace(string(code[match[2]:match[3]])))
+	}
+
+	return pragmas
+}
+
+// FindImports Returns paths of import directives of the Solidity source as they are written
+func FindImports(src []byte) []string {
+	var imports []string
+	code := scrub(src, false)
+	masked := scrub(src, true)
+	for _, match := range importRegex.FindAllIndex(masked, -1) {
+		// The path is the first string literal of the directive
+		path := importPathRegex.FindSubmatch(code[match[0]:match[1]])
+		if path == nil {
+			continue
+		}
+
+		imports = append(imports, string(path[1])+string(path[2]))
+	}
+
+	return imports
+}
+
+// scrub Returns the source with comments replaced by spaces,
+// the contents of string literals are replaced as well if maskStrings is true
+func scrub(src []byte, maskStrings bool) []byte {
+	result := make([]byte, len(src))
+	copy(result, src)
+	for i := 0; i < len(result); i++ {
+		switch c := result[i]; {
+		case c == '"' || c == '\'':
+			for i++; i < len(result) && result[i] != c && result[i] != '\n'; i++ {
+				escaped := result[i] == '\\' && i+1 < len(result)
+				if maskStrings {
+					result[i] = ' '
+				}
+
+				if escaped {
+					i++
+					if maskStrings {
+						result[i] = ' '
+					}
+				}
+			}
+		case c == '/' && i+1 < len(result) && result[i+1] == '/':
+			for ; i < len(result) && result[i] != '\n'; i++ {
+				result[i] = ' '
+			}
+		case c == '/' && i+1 < len(result) && result[i+1] == '*':
+			result[i], result[i+1] = ' ', ' '
+			for i += 2; i < len(result); i++ {
+				if result[i] == '*' && i+1 < len(result) && result[i+1] == '/' {
+					result[i], result[i+1] = ' ', ' '
+					i++
+					break
+				}
+
+				if result[i] != '\n' {
+					result[i] = ' '
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// intersects Reports whether there is a version satisfying both files
+func intersects(a []bool, b []bool) bool {
+	for i := range a {
+		if a[i] && b[i] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isEmpty Reports whether there is no version satisfying the file
+func isEmpty(a []bool) bool {
+	for _, v := range a {
+		if v {
+			return false
+		}
+	}
+
+	return true
+}
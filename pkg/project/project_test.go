@@ -0,0 +1,138 @@
+package project
+
+import (
+	"github.com/Masterminds/semver"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var testVersions = []*semver.Version{
+	semver.MustParse("0.4.26"),
+	semver.MustParse("0.6.12"),
+	semver.MustParse("0.7.6"),
+	semver.MustParse("0.8.3"),
+	semver.MustParse("0.8.19"),
+}
+
+// writeSources Creates source files in the directory
+func writeSources(t *testing.T, dir string, sources map[string]string) {
+	for name, src := range sources {
+		path := filepath.Join(dir, name)
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		assert.NoError(t, err)
+		err = os.WriteFile(path, []byte(src), 0644)
+		assert.NoError(t, err)
+	}
+}
+
+func TestFindPragmas(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "single pragma",
+			input:    "// SPDX-License-Identifier: MIT\npragma solidity ^0.8.0;\ncontract A {}",
+			expected: []string{"^0.8.0"},
+		},
+		{
+			name:     "multiple pragmas",
+			input:    "pragma solidity >=0.6.0 <0.9.0;\npragma   solidity\n0.8.19 ;\npragma abicoder v2;",
+			expected: []string{">=0.6.0 <0.9.0", "0.8.19"},
+		},
+		{
+			name:     "commented out pragmas",
+			input:    "// pragma solidity ^0.4.0;\n/* pragma solidity ^0.5.0;\n */pragma solidity ^0.7.0;",
+			expected: []string{"^0.7.0"},
+		},
+		{
+			name:     "pragma in string literal",
+			input:    "pragma solidity ^0.8.0;\ncontract A { string s = \"// pragma solidity ^0.4.0;\"; }",
+			expected: []string{"^0.8.0"},
+		},
+		{
+			name:     "no pragma",
+			input:    "contract A {}",
+			expected: []string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, FindPragmas([]byte(testCase.input)))
+		})
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	writeSources(t, dir, map[string]string{
+		"contracts/A.sol":            "pragma solidity ^0.8.0;",
+		"contracts/lib/B.sol":        "pragma solidity >=0.6.0 <0.9.0;",
+		"contracts/C.sol":            "pragma solidity abc;",
+		"contracts/README.md":        "pragma solidity ^0.4.0;",
+		"node_modules/dep/D.sol":     "pragma solidity ^0.4.0;",
+		".git/E.sol":                 "pragma solidity ^0.4.0;",
+		"contracts/interfaces/I.sol": "interface I {}",
+	})
+
+	files, err := Scan(dir)
+	assert.NoError(t, err)
+
+	var paths []string
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+
+	assert.Equal(t, []string{
+		filepath.Join("contracts", "A.sol"),
+		filepath.Join("contracts", "C.sol"),
+		filepath.Join("contracts", "interfaces", "I.sol"),
+		filepath.Join("contracts", "lib", "B.sol"),
+	}, paths)
+	assert.Equal(t, []string{"^0.8.0"}, files[0].Pragmas)
+	assert.Equal(t, "Invalid version pragma 'abc': unexpected 'a' at position 0.", files[1].Error)
+	assert.Equal(t, []string{}, files[2].Pragmas)
+}
+
+func TestNewReport(t *testing.T) {
+	t.Run("test compatible files", func(t *testing.T) {
+		dir := t.TempDir()
+		writeSources(t, dir, map[string]string{
+			"A.sol": "pragma solidity ^0.8.0;",
+			"B.sol": "pragma solidity >=0.6.0 <0.8.10;",
+			"C.sol": "contract C {}",
+		})
+
+		files, err := Scan(dir)
+		assert.NoError(t, err)
+
+		report := NewReport(files, testVersions)
+		assert.Equal(t, []string{"0.8.3"}, report.Versions)
+		assert.Equal(t, "0.8.3", report.Newest)
+		assert.Empty(t, report.Conflicts)
+		assert.Empty(t, report.Unsatisfiable)
+	})
+
+	t.Run("test incompatible files", func(t *testing.T) {
+		dir := t.TempDir()
+		writeSources(t, dir, map[string]string{
+			"A.sol": "pragma solidity ^0.8.0;",
+			"B.sol": "pragma solidity ^0.7.0;",
+			"C.sol": "pragma solidity >=0.7.0;",
+			"D.sol": "pragma solidity ^0.5.0;",
+		})
+
+		files, err := Scan(dir)
+		assert.NoError(t, err)
+
+		report := NewReport(files, testVersions)
+		assert.Equal(t, []string{}, report.Versions)
+		assert.Equal(t, "", report.Newest)
+		assert.Equal(t, [][2]string{{"A.sol", "B.sol"}}, report.Conflicts)
+		assert.Equal(t, []string{"D.sol"}, report.Unsatisfiable)
+	})
+}
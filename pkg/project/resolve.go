@@ -0,0 +1,468 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package project
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/Masterminds/semver"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RemappingsFileName The name of the file containing import remappings of a project
+const RemappingsFileName = "remappings.txt"
+
+// Remapping Import remapping (`[context:]prefix=target`)
+type Remapping struct {
+	Context string `json:"context"`
+	Prefix  string `json:"prefix"`
+	Target  string `json:"target"`
+}
+
+// Unit Compilation unit, the entry file with all files it imports directly or transitively
+type Unit struct {
+	Entry string   `json:"entry"`
+	Files []string `json:"files"`
+	// Pragmas Version pragmas of all files, the combined constraint is satisfied if all of them are satisfied
+	Pragmas []string `json:"pragmas"`
+	// Versions satisfying the combined constraint
+	Versions []string `json:"versions"`
+	// Missing Imports which couldn't be found
+	Missing []string `json:"missing,omitempty"`
+	// Errors Invalid version pragmas
+	Errors []string `json:"errors,omitempty"`
+
+	files []*File
+}
+
+// Job Compiler run for a group of compilation units
+type Job struct {
+	Version string   `json:"version"`
+	Entries []string `json:"entries"`
+}
+
+// Resolution Compiler versions of compilation units grouped into the minimum number of compiler runs
+type Resolution struct {
+	Units []*Unit `json:"units"`
+	Jobs  []*Job  `json:"jobs"`
+	// Unresolved Entries of compilation units whose combined constraint is satisfied by none of the versions
+	Unresolved []string `json:"unresolved"`
+}
+
+// Resolver Follows imports of Solidity sources of the project
+//
+// Source unit names (paths of files relative to the root, e.g. `contracts/Token.sol`) are used to identify files
+type Resolver struct {
+	Root       string
+	Remappings []*Remapping
+
+	files map[string]*File
+}
+
+// NewResolver Returns a resolver for the project, reads `remappings.txt` of the project root if it exists
+func NewResolver(root string) (*Resolver, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var remappings []*Remapping
+	data, err := os.ReadFile(filepath.Join(root, RemappingsFileName))
+	if err == nil {
+		remappings, err = ParseRemappings(data)
+		if err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return &Resolver{Root: root, Remappings: remappings, files: make(map[string]*File)}, nil
+}
+
+// Resolve Returns compilation units of the entry files and groups them into compiler runs
+//
+// Versions are expected to be sorted in ascending order (see versions.SortVersions)
+func Resolve(root string, entries []string, versions []*semver.Version) (*Resolution, error) {
+	resolver, err := NewResolver(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var units []*Unit
+	for _, entry := range entries {
+		unit, err := resolver.Unit(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		units = append(units, unit)
+	}
+
+	return GroupUnits(units, versions), nil
+}
+
+// ParseRemappings Returns import remappings, one remapping per line
+func ParseRemappings(data []byte) ([]*Remapping, error) {
+	var remappings []*Remapping
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		eq := strings.Index(line, "=")
+		if eq <= 0 {
+			return nil, fmt.Errorf("invalid remapping '%s'", line)
+		}
+
+		remapping := &Remapping{Prefix: line[:eq], Target: line[eq+1:]}
+		if colon := strings.Index(remapping.Prefix, ":"); colon != -1 {
+			remapping.Context = remapping.Prefix[:colon]
+			remapping.Prefix = remapping.Prefix[colon+1:]
+		}
+
+		if remapping.Prefix == "" {
+			return nil, fmt.Errorf("invalid remapping '%s'", line)
+		}
+
+		remappings = append(remappings, remapping)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return remappings, nil
+}
+
+// Unit Returns the compilation unit of the entry file
+func (r *Resolver) Unit(entry string) (*Unit, error) {
+	entryPath, err := filepath.Abs(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := filepath.Rel(r.Root, entryPath)
+	if err != nil || strings.HasPrefix(name, "..") {
+		return nil, fmt.Errorf("'%s' is outside of the project root '%s'", entry, r.Root)
+	}
+
+	name = filepath.ToSlash(name)
+	unit := &Unit{Entry: name, Files: []string{}, Pragmas: []string{}, Versions: []string{}}
+	visited := make(map[string]bool)
+	queue := []string{name}
+	for len(queue) != 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if visited[name] {
+			continue
+		}
+
+		visited[name] = true
+		file, err := r.file(name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				unit.Missing = append(unit.Missing, name)
+				continue
+			}
+
+			return nil, err
+		}
+
+		unit.files = append(unit.files, file)
+		unit.Files = append(unit.Files, name)
+		for _, p := range file.Pragmas {
+			unit.Pragmas = appendUnique(unit.Pragmas, p)
+		}
+
+		if file.Error != "" {
+			unit.Errors = append(unit.Errors, fmt.Sprintf("%s: %s", name, file.Error))
+		}
+
+		for _, importPath := range file.Imports {
+			queue = append(queue, r.ResolveImport(name, importPath))
+		}
+	}
+
+	return unit, nil
+}
+
+// ResolveImport Returns the source unit name of the import path
+//
+// Relative paths (./, ../) are resolved against the importing file, then remappings are applied
+func (r *Resolver) ResolveImport(importer string, importPath string) string {
+	name := importPath
+	if strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") {
+		name = path.Join(path.Dir(importer), importPath)
+	}
+
+	// The remapping with the longest context wins, then the one with the longest prefix
+	var best *Remapping
+	for _, remapping := range r.Remappings {
+		if !strings.HasPrefix(importer, remapping.Context) || !strings.HasPrefix(name, remapping.Prefix) {
+			continue
+		}
+
+		if best == nil || len(remapping.Context) > len(best.Context) ||
+			(len(remapping.Context) == len(best.Context) && len(remapping.Prefix) > len(best.Prefix)) {
+			best = remapping
+		}
+	}
+
+	if best != nil {
+		name = best.Target + strings.TrimPrefix(name, best.Prefix)
+	}
+
+	return path.Clean(name)
+}
+
+// file Returns the parsed file of the source unit, looks for it in the root and in `node_modules`
+func (r *Resolver) file(name string) (*File, error) {
+	if file, ok := r.files[name]; ok {
+		return file, nil
+	}
+
+	file, err := ParseFile(filepath.Join(r.Root, filepath.FromSlash(name)))
+	if os.IsNotExist(err) {
+		file, err = ParseFile(filepath.Join(r.Root, "node_modules", filepath.FromSlash(name)))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	file.Path = name
+	r.files[name] = file
+	return file, nil
+}
+
+// Matches Reports whether the version satisfies version pragmas of all files of the compilation unit
+func (r *Unit) Matches(version *semver.Version) bool {
+	for _, file := range r.files {
+		if !file.Matches(version) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GroupUnits Returns compilation units grouped into the minimum number of compiler runs
+//
+// The smallest set of versions satisfying all units is searched exhaustively, newer versions are preferred on a tie.
+// Each run then uses the version of the set satisfying the most of the remaining units, the newest one on a tie.
+// Versions are expected to be sorted in ascending order (see versions.SortVersions)
+func GroupUnits(units []*Unit, versions []*semver.Version) *Resolution {
+	resolution := &Resolution{Units: units, Jobs: []*Job{}, Unresolved: []string{}}
+	var remaining []*Unit
+	for _, unit := range units {
+		unit.Versions = []string{}
+		for _, version := range versions {
+			if unit.Matches(version) {
+				unit.Versions = append(unit.Versions, version.Original())
+			}
+		}
+
+		if len(unit.Versions) == 0 {
+			resolution.Unresolved = append(resolution.Unresolved, unit.Entry)
+			continue
+		}
+
+		remaining = append(remaining, unit)
+	}
+
+	versions = minimumCover(remaining, versions)
+	for len(remaining) != 0 {
+		var best *semver.Version
+		bestCount := 0
+		for i := len(versions) - 1; i >= 0; i-- {
+			count := 0
+			for _, unit := range remaining {
+				if unit.Matches(versions[i]) {
+					count++
+				}
+			}
+
+			if count > bestCount {
+				best, bestCount = versions[i], count
+			}
+		}
+
+		job := &Job{Version: best.Original()}
+		var rest []*Unit
+		for _, unit := range remaining {
+			if unit.Matches(best) {
+				job.Entries = append(job.Entries, unit.Entry)
+			} else {
+				rest = append(rest, unit)
+			}
+		}
+
+		sort.Strings(job.Entries)
+		resolution.Jobs = append(resolution.Jobs, job)
+		remaining = rest
+	}
+
+	return resolution
+}
+
+// minimumCover Returns the smallest set of versions satisfying all units in ascending order
+//
+// Each unit must be satisfied by at least one of the versions
+func minimumCover(units []*Unit, versions []*semver.Version) []*semver.Version {
+	// Versions satisfying the same units are interchangeable, only the newest one is kept
+	var candidates []*semver.Version
+	var covers [][]bool
+	seen := make(map[string]bool)
+	for i := len(versions) - 1; i >= 0; i-- {
+		cover := make([]bool, len(units))
+		key := make([]byte, len(units))
+		for j, unit := range units {
+			cover[j] = unit.Matches(versions[i])
+			key[j] = '0'
+			if cover[j] {
+				key[j] = '1'
+			}
+		}
+
+		if !seen[string(key)] {
+			seen[string(key)] = true
+			candidates = append(candidates, versions[i])
+			covers = append(covers, cover)
+		}
+	}
+
+	// A version satisfying a subset of units of another version is never needed
+	search := &coverSearch{counts: make([]int, len(units))}
+	var kept []*semver.Version
+	for i, cover := range covers {
+		dominated := false
+		for j, other := range covers {
+			if i != j && isSubset(cover, other) {
+				dominated = true
+				break
+			}
+		}
+
+		if !dominated {
+			kept = append(kept, candidates[i])
+			search.covers = append(search.covers, cover)
+		}
+	}
+
+	search.search(nil)
+	var result []*semver.Version
+	for _, i := range search.best {
+		result = append(result, kept[i])
+	}
+
+	sort.Sort(semver.Collection(result))
+	return result
+}
+
+// coverSearch Branch and bound search of the smallest set cover
+type coverSearch struct {
+	// covers Units satisfied by each candidate version, newest versions first
+	covers [][]bool
+	// counts Number of chosen candidates satisfying each unit
+	counts []int
+	best   []int
+}
+
+// search Extends the chosen candidates until all units are satisfied, keeps the smallest set found
+func (r *coverSearch) search(chosen []int) {
+	// Branches on the unsatisfied unit with the fewest candidates
+	unit, fewest := -1, 0
+	for j, count := range r.counts {
+		if count != 0 {
+			continue
+		}
+
+		n := 0
+		for _, cover := range r.covers {
+			if cover[j] {
+				n++
+			}
+		}
+
+		if unit == -1 || n < fewest {
+			unit, fewest = j, n
+		}
+	}
+
+	if unit == -1 {
+		r.best = append([]int(nil), chosen...)
+		return
+	}
+
+	if r.best != nil && len(chosen)+1 >= len(r.best) {
+		return
+	}
+
+	for i, cover := range r.covers {
+		if !cover[unit] {
+			continue
+		}
+
+		r.choose(i, 1)
+		r.search(append(chosen, i))
+		r.choose(i, -1)
+	}
+}
+
+// choose Adds (1) or removes (-1) the candidate to the chosen ones
+func (r *coverSearch) choose(i int, delta int) {
+	for j, satisfied := range r.covers[i] {
+		if satisfied {
+			r.counts[j] += delta
+		}
+	}
+}
+
+// isSubset Reports whether all units of the cover are in the other cover
+func isSubset(cover []bool, other []bool) bool {
+	for j := range cover {
+		if cover[j] && !other[j] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// appendUnique Appends the value to the slice if it isn't there yet
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
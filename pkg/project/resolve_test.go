@@ -0,0 +1,144 @@
+package project
+
+import (
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindImports(t *testing.T) {
+	src := `pragma solidity ^0.8.0;
+import "./A.sol";
+import './B.sol' as B;
+import * as C from "../C.sol";
+import {D, E as F} from "lib/D.sol";
+// import "./Commented.sol";
+contract G { string s = "import './String.sol';"; }`
+	expected := []string{"./A.sol", "./B.sol", "../C.sol", "lib/D.sol"}
+	assert.Equal(t, expected, FindImports([]byte(src)))
+}
+
+func TestParseRemappings(t *testing.T) {
+	t.Run("test valid remappings", func(t *testing.T) {
+		data := []byte("@openzeppelin/=lib/openzeppelin-contracts/\n\nsrc:ds-test/=lib/ds-test/src/\n")
+		expected := []*Remapping{
+			{Prefix: "@openzeppelin/", Target: "lib/openzeppelin-contracts/"},
+			{Context: "src", Prefix: "ds-test/", Target: "lib/ds-test/src/"},
+		}
+
+		remappings, err := ParseRemappings(data)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, remappings)
+	})
+
+	t.Run("test invalid remapping", func(t *testing.T) {
+		_, err := ParseRemappings([]byte("@openzeppelin/"))
+		assert.EqualError(t, err, "invalid remapping '@openzeppelin/'")
+	})
+}
+
+func TestResolveImport(t *testing.T) {
+	resolver := &Resolver{
+		Remappings: []*Remapping{
+			{Prefix: "@oz/", Target: "lib/oz/"},
+			{Prefix: "@oz/token/", Target: "lib/oz-token/"},
+			{Context: "test", Prefix: "@oz/", Target: "lib/oz-test/"},
+		},
+	}
+	testCases := []struct {
+		importer string
+		input    string
+		expected string
+	}{
+		{"contracts/A.sol", "./B.sol", "contracts/B.sol"},
+		{"contracts/token/A.sol", "../B.sol", "contracts/B.sol"},
+		{"contracts/A.sol", "contracts/lib/C.sol", "contracts/lib/C.sol"},
+		{"contracts/A.sol", "@oz/access/Ownable.sol", "lib/oz/access/Ownable.sol"},
+		{"contracts/A.sol", "@oz/token/ERC20.sol", "lib/oz-token/ERC20.sol"},
+		{"test/A.t.sol", "@oz/token/ERC20.sol", "lib/oz-test/token/ERC20.sol"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.input, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, resolver.ResolveImport(testCase.importer, testCase.input))
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	writeSources(t, dir, map[string]string{
+		"remappings.txt":                 "@oz/=lib/oz/\n",
+		"contracts/Token.sol":            "pragma solidity ^0.8.0;\nimport \"@oz/ERC20.sol\";\nimport \"./utils/Math.sol\";",
+		"contracts/utils/Math.sol":       "pragma solidity >=0.6.0 <0.8.10;\nimport \"../Token.sol\";",
+		"lib/oz/ERC20.sol":               "pragma solidity >=0.6.0;",
+		"contracts/Legacy.sol":           "pragma solidity ^0.6.0;\nimport \"./Missing.sol\";",
+		"contracts/Vault.sol":            "pragma solidity >=0.7.0;",
+		"contracts/Old.sol":              "pragma solidity ^0.5.0;",
+		"node_modules/dep/Interface.sol": "pragma solidity >=0.4.0;",
+		"contracts/Proxy.sol":            "pragma solidity ^0.7.0;\nimport \"dep/Interface.sol\";",
+	})
+
+	entries := []string{
+		filepath.Join(dir, "contracts", "Token.sol"),
+		filepath.Join(dir, "contracts", "Legacy.sol"),
+		filepath.Join(dir, "contracts", "Vault.sol"),
+		filepath.Join(dir, "contracts", "Old.sol"),
+		filepath.Join(dir, "contracts", "Proxy.sol"),
+	}
+
+	resolution, err := Resolve(dir, entries, testVersions)
+	assert.NoError(t, err)
+
+	token := resolution.Units[0]
+	assert.Equal(t, "contracts/Token.sol", token.Entry)
+	assert.Equal(t, []string{"contracts/Token.sol", "lib/oz/ERC20.sol", "contracts/utils/Math.sol"}, token.Files)
+	assert.Equal(t, []string{"^0.8.0", ">=0.6.0", ">=0.6.0 <0.8.10"}, token.Pragmas)
+	assert.Equal(t, []string{"0.8.3"}, token.Versions)
+
+	legacy := resolution.Units[1]
+	assert.Equal(t, []string{"contracts/Missing.sol"}, legacy.Missing)
+	assert.Equal(t, []string{"0.6.12"}, legacy.Versions)
+
+	proxy := resolution.Units[4]
+	assert.Equal(t, []string{"contracts/Proxy.sol", "dep/Interface.sol"}, proxy.Files)
+
+	assert.Equal(t, []string{"contracts/Old.sol"}, resolution.Unresolved)
+	assert.Equal(t, []*Job{
+		{Version: "0.8.3", Entries: []string{"contracts/Token.sol", "contracts/Vault.sol"}},
+		{Version: "0.7.6", Entries: []string{"contracts/Proxy.sol"}},
+		{Version: "0.6.12", Entries: []string{"contracts/Legacy.sol"}},
+	}, resolution.Jobs)
+}
+
+func TestResolveMinimumJobs(t *testing.T) {
+	// The version satisfying the most units (0.8.3) isn't part of the smallest set of versions,
+	// picking it first would require three compiler runs instead of two
+	dir := t.TempDir()
+	writeSources(t, dir, map[string]string{
+		"A.sol": "pragma solidity 0.8.3 || 0.7.6;",
+		"B.sol": "pragma solidity 0.8.3 || 0.7.6;",
+		"C.sol": "pragma solidity 0.7.6;",
+		"D.sol": "pragma solidity 0.8.3 || 0.6.12;",
+		"E.sol": "pragma solidity 0.8.3 || 0.6.12;",
+		"F.sol": "pragma solidity 0.6.12;",
+	})
+
+	var entries []string
+	for _, name := range []string{"A.sol", "B.sol", "C.sol", "D.sol", "E.sol", "F.sol"} {
+		entries = append(entries, filepath.Join(dir, name))
+	}
+
+	resolution, err := Resolve(dir, entries, testVersions)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Job{
+		{Version: "0.7.6", Entries: []string{"A.sol", "B.sol", "C.sol"}},
+		{Version: "0.6.12", Entries: []string{"D.sol", "E.sol", "F.sol"}},
+	}, resolution.Jobs)
+}
+
+func TestResolveOutsideRoot(t *testing.T) {
+	dir := t.TempDir()
+	_, err := Resolve(filepath.Join(dir, "project"), []string{filepath.Join(dir, "A.sol")}, testVersions)
+	assert.Error(t, err)
+}
@@ -22,19 +22,29 @@ go-solc-select is a tool written in Golang for managing and switching between ve
 package solc
 
 import (
+	"context"
 	"fmt"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/internal/utils"
 	"github.com/fabelx/go-solc-select/pkg/config"
+	"github.com/fabelx/go-solc-select/pkg/installer"
 	ver "github.com/fabelx/go-solc-select/pkg/versions"
 	"log"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
 )
 
 // Execute the entrypoint called by main.go
 func Execute() {
-	args := os.Args[1:]
-	var currentVersion, err = ver.GetCurrent()
+	// Interruption aborts the installation of a missing version
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	currentVersion, args, err := resolveVersion(ctx, os.Args[1:])
+	stop()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -43,16 +53,66 @@ func Execute() {
 	filePath := filepath.Join(config.SolcArtifacts, name, name)
 	cmd := exec.Command(filePath, args...)
 	cmd.Stdin = os.Stdin
-	out, err := cmd.CombinedOutput()
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	code, err := utils.Run(cmd)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	os.Exit(code)
+}
 
-	if err == nil {
-		fmt.Print(string(out))
-	} else if werr, ok := err.(*exec.ExitError); ok {
-		if s := werr.Error(); s != "0" {
-			fmt.Print(string(out))
+// resolveVersion Returns the compiler version to run and the arguments to pass to the compiler
+//
+// A leading `+<version>` or `+latest` argument overrides the current version for a single run,
+// the argument itself is not passed to the compiler
+func resolveVersion(ctx context.Context, args []string) (string, []string, error) {
+	if len(args) == 0 || !strings.HasPrefix(args[0], "+") {
+		version, err := ver.GetCurrent()
+		return version, args, err
+	}
+
+	version := strings.TrimPrefix(args[0], "+")
+	args = args[1:]
+	autoInstall, _ := strconv.ParseBool(os.Getenv(config.AutoInstallEnv))
+
+	if version == "latest" {
+		versions := ver.GetInstalled()
+		if autoInstall {
+			availableVersions, err := ver.GetAvailable(ctx)
+			if err != nil {
+				return "", nil, err
+			}
+
+			versions = availableVersions
 		}
 
-	} else {
-		log.Fatal(err)
+		sorted := ver.SortVersions(versions)
+		if len(sorted) == 0 {
+			return "", nil, &errors.NoCompilerSelected{}
+		}
+
+		version = sorted[len(sorted)-1].String()
+	}
+
+	match := config.ValidSemVer.MatchString(version)
+	if !match {
+		return "", nil, &errors.UnknownVersionError{Version: version}
+	}
+
+	installedVersions := ver.GetInstalled()
+	if installedVersions[version] == "" {
+		if !autoInstall {
+			return "", nil, &errors.NotInstalledError{Version: version}
+		}
+
+		fmt.Fprintf(os.Stderr, "Installing solc %s...\n", version)
+		_, err := installer.InstallSolc(ctx, version, nil)
+		if err != nil {
+			return "", nil, err
+		}
 	}
+
+	return version, args, nil
 }
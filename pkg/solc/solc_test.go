@@ -0,0 +1,122 @@
+package solc
+
+import (
+	"context"
+	"fmt"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var testCurrentVersion = "0.4.9"
+var testVersions = map[string]string{
+	testCurrentVersion: testCurrentVersion,
+	"0.5.1":            "0.5.1",
+	"0.8.17":           "0.8.17",
+}
+
+func TestMain(m *testing.M) {
+	err := setup()
+	if err != nil {
+		log.Fatalf("Failed to run tests during setup. Error: %v", err)
+	}
+
+	code := m.Run()
+	shutdown()
+	os.Exit(code)
+}
+
+// setup Setups test environment
+func setup() error {
+	// creates dirs for testing
+	config.SolcDir = filepath.Join(config.HomeDir, ".test-gsolc-select")
+	config.SolcArtifacts = filepath.Join(config.SolcDir, "artifacts")
+	config.SolcLocks = filepath.Join(config.SolcDir, "locks")
+	config.SolcCache = filepath.Join(config.SolcDir, "cache")
+	config.KnownHashesPath = filepath.Join(config.SolcDir, "known-hashes.json")
+	err := os.MkdirAll(config.SolcArtifacts, 0755)
+	if err != nil {
+		return err
+	}
+
+	// initializes current global version
+	config.CurrentVersionFilePath = filepath.Join(config.SolcDir, "global-version")
+	err = os.WriteFile(config.CurrentVersionFilePath, []byte(testCurrentVersion), 0755)
+	if err != nil {
+		return err
+	}
+
+	// adds fake solc compilers
+	for _, v := range testVersions {
+		name := fmt.Sprintf("solc-%s", v)
+		dirPath := filepath.Join(config.SolcArtifacts, name)
+		os.Mkdir(dirPath, 0755)
+		fakeFilePath := filepath.Join(dirPath, name)
+		os.WriteFile(fakeFilePath, []byte(""), 0755)
+	}
+	return nil
+}
+
+// shutdown Removes all test files and dirs
+func shutdown() {
+	os.RemoveAll(config.SolcDir)
+}
+
+func TestResolveVersion(t *testing.T) {
+	testCases := []struct {
+		name         string
+		input        []string
+		expected     string
+		expectedArgs []string
+		err          error
+	}{
+		{
+			name:         "test current version",
+			input:        []string{"--version"},
+			expected:     testCurrentVersion,
+			expectedArgs: []string{"--version"},
+			err:          nil,
+		},
+		{
+			name:         "test version override",
+			input:        []string{"+0.5.1", "--bin", "Contract.sol"},
+			expected:     "0.5.1",
+			expectedArgs: []string{"--bin", "Contract.sol"},
+			err:          nil,
+		},
+		{
+			name:         "test latest version override",
+			input:        []string{"+latest", "--version"},
+			expected:     "0.8.17",
+			expectedArgs: []string{"--version"},
+			err:          nil,
+		},
+		{
+			name:         "test not installed version override",
+			input:        []string{"+0.0.0", "--version"},
+			expected:     "",
+			expectedArgs: nil,
+			err:          &errors.NotInstalledError{Version: "0.0.0"},
+		},
+		{
+			name:         "test invalid version override",
+			input:        []string{"+foo"},
+			expected:     "",
+			expectedArgs: nil,
+			err:          &errors.UnknownVersionError{Version: "foo"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			version, args, err := resolveVersion(context.Background(), testCase.input)
+			assert.Equal(t, testCase.err, err)
+			assert.Equal(t, testCase.expected, version)
+			assert.Equal(t, testCase.expectedArgs, args)
+		})
+	}
+}
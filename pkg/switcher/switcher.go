@@ -22,20 +22,48 @@ go-solc-select is a tool written in Golang for managing and switching between ve
 package switcher
 
 import (
+	"context"
 	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/internal/lock"
 	"github.com/fabelx/go-solc-select/pkg/config"
 	ver "github.com/fabelx/go-solc-select/pkg/versions"
 	"os"
+	"path/filepath"
 )
 
 // SwitchSolc Returns an error if the version switch failed
+//
+// The `global-version` file is locked, so the version can't be removed by another process while switching to it
 func SwitchSolc(version string) error {
+	l, err := lock.Global(context.Background())
+	if err != nil {
+		return err
+	}
+	defer l.Release()
+
+	installedVersions := ver.GetInstalled()
+	if installedVersions[version] == "" {
+		return &errors.NotInstalledError{Version: version}
+	}
+
+	err = os.WriteFile(config.CurrentVersionFilePath, []byte(version), 0755)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SwitchLocalSolc Pins the version for the passed directory, returns an error if the version switch failed
+//
+// Writes a `.solc-version` file, which takes precedence over the global version in the directory and its subdirectories
+func SwitchLocalSolc(dir string, version string) error {
 	installedVersions := ver.GetInstalled()
 	if installedVersions[version] == "" {
 		return &errors.NotInstalledError{Version: version}
 	}
 
-	err := os.WriteFile(config.CurrentVersionFilePath, []byte(version), 0755)
+	err := os.WriteFile(filepath.Join(dir, config.LocalVersionFileName), []byte(version+"\n"), 0644)
 	if err != nil {
 		return err
 	}
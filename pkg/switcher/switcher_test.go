@@ -34,6 +34,7 @@ func setup() error {
 	// creates dirs for testing
 	config.SolcDir = filepath.Join(config.HomeDir, ".test-gsolc-select")
 	config.SolcArtifacts = filepath.Join(config.SolcDir, "artifacts")
+	config.SolcLocks = filepath.Join(config.SolcDir, "locks")
 	err := os.MkdirAll(config.SolcArtifacts, 0755)
 	if err != nil {
 		return err
@@ -96,3 +97,25 @@ func TestSwitchSolc(t *testing.T) {
 		})
 	}
 }
+
+func TestSwitchLocalSolc(t *testing.T) {
+	dir := filepath.Join(config.SolcDir, "project")
+	err := os.MkdirAll(dir, 0755)
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	t.Run("test failed switch local version", func(t *testing.T) {
+		err := SwitchLocalSolc(dir, "0.0.0")
+		assert.Equal(t, &errors.NotInstalledError{Version: "0.0.0"}, err)
+		assert.NoFileExists(t, filepath.Join(dir, config.LocalVersionFileName))
+	})
+
+	t.Run("test success switch local version", func(t *testing.T) {
+		err := SwitchLocalSolc(dir, "0.5.1")
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(filepath.Join(dir, config.LocalVersionFileName))
+		assert.NoError(t, err)
+		assert.Equal(t, "0.5.1\n", string(data))
+	})
+}
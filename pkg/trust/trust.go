@@ -0,0 +1,204 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package trust
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/internal/lock"
+	"github.com/fabelx/go-solc-select/internal/utils"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Hashes Checksums of a solc build pinned when the build was first seen
+type Hashes struct {
+	Keccak256 string    `json:"keccak256"`
+	Sha256    string    `json:"sha256"`
+	FirstSeen time.Time `json:"first_seen"`
+}
+
+// database Known checksums of solc builds by platform and version
+type database map[string]map[string]*Hashes
+
+// mu Serializes updates of the database within the process, the file lock serializes them between processes
+var mu sync.Mutex
+
+// read Returns the database stored in config.KnownHashesPath, an empty database if the file doesn't exist
+func read() (database, error) {
+	db := database{}
+	data, err := os.ReadFile(config.KnownHashesPath)
+	if os.IsNotExist(err) {
+		return db, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, &db); err != nil {
+		return nil, fmt.Errorf("failed to parse '%s': %w", config.KnownHashesPath, err)
+	}
+
+	return db, nil
+}
+
+// write Stores the database in config.KnownHashesPath, the file is replaced atomically
+func write(db database) error {
+	data, err := json.MarshalIndent(db, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(config.KnownHashesPath)
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(dir, "known-hashes-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), config.KnownHashesPath)
+}
+
+// update Passes the database to the function under the lock and stores it if the function reports a change
+func update(ctx context.Context, fn func(db database) (bool, error)) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	l, err := lock.KnownHashes(ctx)
+	if err != nil {
+		return err
+	}
+	defer l.Release()
+
+	db, err := read()
+	if err != nil {
+		return err
+	}
+
+	changed, err := fn(db)
+	if changed {
+		if writeErr := write(db); err == nil {
+			err = writeErr
+		}
+	}
+
+	return err
+}
+
+// pin Remembers the checksums of the build unless its version is already known, reports whether the build was pinned
+func (db database) pin(platform string, build *utils.BuildData) bool {
+	if db[platform] == nil {
+		db[platform] = map[string]*Hashes{}
+	}
+
+	if db[platform][build.Version] != nil {
+		return false
+	}
+
+	db[platform][build.Version] = &Hashes{Keccak256: build.Keccak256, Sha256: build.Sha256, FirstSeen: time.Now().UTC()}
+	return true
+}
+
+// Remember Pins the checksums of builds seen for the first time, checksums of known versions are never overwritten
+func Remember(ctx context.Context, platform string, builds []*utils.BuildData) error {
+	return update(ctx, func(db database) (bool, error) {
+		var changed bool
+		for _, build := range builds {
+			changed = db.pin(platform, build) || changed
+		}
+
+		return changed, nil
+	})
+}
+
+// Check Returns an error if the checksums of the build differ from the checksums pinned for its version
+//
+// The checksums of a version seen for the first time are pinned
+func Check(ctx context.Context, platform string, build *utils.BuildData) error {
+	return update(ctx, func(db database) (bool, error) {
+		if db.pin(platform, build) {
+			return true, nil
+		}
+
+		known := db[platform][build.Version]
+		if known.Sha256 != build.Sha256 {
+			return false, &errors.ChecksumChangedError{Version: build.Version, Platform: platform, HashFunc: "Sha256", Known: known.Sha256, Received: build.Sha256}
+		}
+
+		if known.Keccak256 != build.Keccak256 {
+			return false, &errors.ChecksumChangedError{Version: build.Version, Platform: platform, HashFunc: "Keccak256", Known: known.Keccak256, Received: build.Keccak256}
+		}
+
+		return false, nil
+	})
+}
+
+// Reset Forgets the checksums of the version, the checksums seen next are pinned
+// Returns false if the checksums of the version weren't known
+func Reset(ctx context.Context, platform string, version string) (bool, error) {
+	var known bool
+	err := update(ctx, func(db database) (bool, error) {
+		if db[platform][version] == nil {
+			return false, nil
+		}
+
+		known = true
+		delete(db[platform], version)
+		return true, nil
+	})
+
+	return known, err
+}
+
+// GetKnown Returns the pinned checksums of the platform by version
+func GetKnown(platform string) (map[string]*Hashes, error) {
+	db, err := read()
+	if err != nil {
+		return nil, err
+	}
+
+	if db[platform] == nil {
+		return map[string]*Hashes{}, nil
+	}
+
+	return db[platform], nil
+}
@@ -0,0 +1,75 @@
+package trust
+
+import (
+	"context"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/internal/utils"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	config.SolcDir = filepath.Join(config.HomeDir, ".test-gsolc-select")
+	config.SolcLocks = filepath.Join(config.SolcDir, "locks")
+	config.KnownHashesPath = filepath.Join(config.SolcDir, "known-hashes.json")
+
+	code := m.Run()
+	os.RemoveAll(config.SolcDir)
+	os.Exit(code)
+}
+
+func TestTrust(t *testing.T) {
+	ctx := context.Background()
+	platform := config.LinuxAmd64
+	build := &utils.BuildData{Version: "0.8.0", Keccak256: "0x01", Sha256: "0x02"}
+	defer os.Remove(config.KnownHashesPath)
+
+	t.Run("pins builds seen for the first time", func(t *testing.T) {
+		err := Remember(ctx, platform, []*utils.BuildData{build})
+		assert.NoError(t, err)
+
+		// checksums of a known version are never overwritten
+		err = Remember(ctx, platform, []*utils.BuildData{{Version: "0.8.0", Keccak256: "0x03", Sha256: "0x04"}})
+		assert.NoError(t, err)
+
+		known, err := GetKnown(platform)
+		assert.NoError(t, err)
+		assert.Equal(t, "0x01", known["0.8.0"].Keccak256)
+		assert.Equal(t, "0x02", known["0.8.0"].Sha256)
+		assert.False(t, known["0.8.0"].FirstSeen.IsZero())
+
+		known, err = GetKnown(config.MacosxAmd64)
+		assert.NoError(t, err)
+		assert.Empty(t, known)
+	})
+
+	t.Run("accepts known checksums", func(t *testing.T) {
+		assert.NoError(t, Check(ctx, platform, build))
+		assert.NoError(t, Check(ctx, config.MacosxAmd64, &utils.BuildData{Version: "0.8.0", Keccak256: "0x05", Sha256: "0x06"}))
+	})
+
+	t.Run("refuses changed checksums", func(t *testing.T) {
+		err := Check(ctx, platform, &utils.BuildData{Version: "0.8.0", Keccak256: "0x01", Sha256: "0x04"})
+		assert.Equal(t, &errors.ChecksumChangedError{Version: "0.8.0", Platform: platform, HashFunc: "Sha256", Known: "0x02", Received: "0x04"}, err)
+
+		err = Check(ctx, platform, &utils.BuildData{Version: "0.8.0", Keccak256: "0x03", Sha256: "0x02"})
+		assert.Equal(t, &errors.ChecksumChangedError{Version: "0.8.0", Platform: platform, HashFunc: "Keccak256", Known: "0x01", Received: "0x03"}, err)
+	})
+
+	t.Run("reset forgets checksums", func(t *testing.T) {
+		known, err := Reset(ctx, platform, "0.8.0")
+		assert.NoError(t, err)
+		assert.True(t, known)
+
+		known, err = Reset(ctx, platform, "0.8.0")
+		assert.NoError(t, err)
+		assert.False(t, known)
+
+		changed := &utils.BuildData{Version: "0.8.0", Keccak256: "0x03", Sha256: "0x04"}
+		assert.NoError(t, Check(ctx, platform, changed))
+		assert.Error(t, Check(ctx, platform, build))
+	})
+}
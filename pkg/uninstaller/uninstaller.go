@@ -22,7 +22,9 @@ go-solc-select is a tool written in Golang for managing and switching between ve
 package uninstaller
 
 import (
+	"context"
 	"fmt"
+	"github.com/fabelx/go-solc-select/internal/lock"
 	"github.com/fabelx/go-solc-select/pkg/config"
 	ver "github.com/fabelx/go-solc-select/pkg/versions"
 	"os"
@@ -30,16 +32,31 @@ import (
 )
 
 // UninstallSolc Returns nil if success
+//
+// The version and the `global-version` file are locked, so the version can't be removed
+// while another process is installing it or switching to it
 func UninstallSolc(version string) error {
-	// reset the current version in the file if it gets deleted
-	var currentVersion, _ = ver.GetCurrent()
+	versionLock, err := lock.Version(context.Background(), version)
+	if err != nil {
+		return err
+	}
+	defer versionLock.Release()
+
+	globalLock, err := lock.Global(context.Background())
+	if err != nil {
+		return err
+	}
+	defer globalLock.Release()
+
+	// reset the global version in the file if it gets deleted
+	var currentVersion, _ = ver.GetGlobal()
 	if currentVersion == version {
 		os.WriteFile(config.CurrentVersionFilePath, []byte(""), 0755)
 	}
 
 	// remove a dir with solc compiler artifacts
 	folderPath := filepath.Join(config.SolcArtifacts, fmt.Sprintf("solc-%s", version))
-	err := os.RemoveAll(folderPath)
+	err = os.RemoveAll(folderPath)
 	if err != nil {
 		return err
 	}
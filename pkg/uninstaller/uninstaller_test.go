@@ -32,6 +32,7 @@ func setup() error {
 	// creates dirs for testing
 	config.SolcDir = filepath.Join(config.HomeDir, ".test-gsolc-select")
 	config.SolcArtifacts = filepath.Join(config.SolcDir, "artifacts")
+	config.SolcLocks = filepath.Join(config.SolcDir, "locks")
 	err := os.MkdirAll(config.SolcArtifacts, 0755)
 	if err != nil {
 		return err
@@ -0,0 +1,215 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/internal/lock"
+	"github.com/fabelx/go-solc-select/internal/utils"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"github.com/fabelx/go-solc-select/pkg/manifest"
+	"github.com/fabelx/go-solc-select/pkg/trust"
+	ver "github.com/fabelx/go-solc-select/pkg/versions"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Source The checksums installed files are compared to
+type Source string
+
+const (
+	// SourceManifest Checksums recorded in the manifest at installation
+	SourceManifest Source = "manifest"
+	// SourcePinned Checksums pinned when the build was first seen, used for versions installed without a manifest
+	SourcePinned Source = "pinned"
+	// SourceList Checksums published in the list of builds, used for versions installed without a manifest
+	// whose checksums were never pinned
+	SourceList Source = "list"
+)
+
+// Result Outcome of the verification of an installed compiler version
+type Result struct {
+	Version string `json:"version"`
+	Source  Source `json:"source,omitempty"`
+	Err     error  `json:"-"`
+}
+
+// builds Fetches the list of builds once and only if some version has no manifest
+type builds struct {
+	platform ver.Platform
+	builds   []*utils.BuildData
+	err      error
+	fetched  bool
+}
+
+func (r *builds) get(ctx context.Context) ([]*utils.BuildData, error) {
+	if !r.fetched {
+		r.builds, r.err = r.platform.GetBuilds(ctx)
+		r.fetched = true
+	}
+
+	return r.builds, r.err
+}
+
+// verify Compares the files of the installed version with the checksums of its manifest,
+// or with the pinned checksums or the checksums of the list of builds if the version has no manifest
+//
+// The manifest is stored next to the files it describes and may be rewritten along with them,
+// so the compiler binary is also compared with the checksums of its build and the pinned checksums
+func verify(ctx context.Context, b *builds, version string) (Source, error) {
+	folder := filepath.Join(config.SolcArtifacts, fmt.Sprintf("solc-%s", version))
+
+	// The list publishes checksums of archives of old compiler versions for windows, not of the extracted files
+	oldWindows := b.platform.GetName() == config.WindowsAmd64 && utils.IsOldWindowsVersion(version)
+	var expected []*manifest.FileHashes
+	if !oldWindows {
+		known, err := trust.GetKnown(b.platform.GetName())
+		if err != nil {
+			return "", err
+		}
+
+		if hashes := known[version]; hashes != nil {
+			expected = append(expected, &manifest.FileHashes{Sha256: hashes.Sha256, Keccak256: hashes.Keccak256})
+		}
+	}
+
+	m, err := manifest.Read(folder)
+	if err == nil {
+		err = m.Verify(folder)
+		if err != nil {
+			return SourceManifest, err
+		}
+
+		if m.Build != nil && !oldWindows {
+			expected = append(expected, &manifest.FileHashes{Sha256: m.Build.Sha256, Keccak256: m.Build.Keccak256})
+		}
+
+		return SourceManifest, verifyBinary(folder, version, expected)
+	}
+
+	if !os.IsNotExist(err) {
+		return SourceManifest, err
+	}
+
+	if oldWindows {
+		return "", &errors.UnverifiableError{Version: version}
+	}
+
+	if len(expected) != 0 {
+		return SourcePinned, verifyBinary(folder, version, expected)
+	}
+
+	list, err := b.get(ctx)
+	if err != nil {
+		return SourceList, err
+	}
+
+	build, err := ver.GetBuild(list, version)
+	if err != nil {
+		return "", &errors.UnverifiableError{Version: version}
+	}
+
+	return SourceList, verifyBinary(folder, version, []*manifest.FileHashes{{Sha256: build.Sha256, Keccak256: build.Keccak256}})
+}
+
+// verifyBinary Compares the compiler binary of the version in the folder with each of the expected checksums
+func verifyBinary(folder string, version string, expected []*manifest.FileHashes) error {
+	if len(expected) == 0 {
+		return nil
+	}
+
+	name := fmt.Sprintf("solc-%s", version)
+	checksum, err := utils.HashFile(filepath.Join(folder, name))
+	if err != nil {
+		return err
+	}
+
+	for _, hashes := range expected {
+		if checksum.Sha256() != hashes.Sha256 {
+			return &errors.ModifiedFileError{Version: version, File: name, HashFunc: "Sha256"}
+		}
+
+		if checksum.Keccak256() != hashes.Keccak256 {
+			return &errors.ModifiedFileError{Version: version, File: name, HashFunc: "Keccak256"}
+		}
+	}
+
+	return nil
+}
+
+// VerifySolc Returns the result of the verification and nil if the installed files of the version are intact
+func VerifySolc(ctx context.Context, version string) (*Result, error) {
+	results, err := VerifySolcs(ctx, []string{version})
+	if err != nil {
+		return nil, err
+	}
+
+	return results[0], results[0].Err
+}
+
+// VerifySolcs Recomputes the checksums of the installed files of the versions
+// Returns the results of the verification of each version in the passed order and error
+//
+// A version is locked during its verification, so a concurrent installation isn't mistaken for a broken one
+func VerifySolcs(ctx context.Context, versions []string) ([]*Result, error) {
+	platform, err := ver.GetPlatform(runtime.GOOS)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &builds{platform: platform}
+	installed := ver.GetInstalled()
+	var results []*Result
+	for _, version := range versions {
+		result := &Result{Version: version}
+		results = append(results, result)
+		if installed[version] == "" {
+			result.Err = &errors.NotInstalledError{Version: version}
+			continue
+		}
+
+		l, err := lock.Version(ctx, version)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Source, result.Err = verify(ctx, b, version)
+		l.Release()
+	}
+
+	return results, nil
+}
+
+// Failed Returns the results of the versions which failed verification
+func Failed(results []*Result) []*Result {
+	var failed []*Result
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+
+	return failed
+}
@@ -0,0 +1,176 @@
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/internal/utils"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"github.com/fabelx/go-solc-select/pkg/manifest"
+	"github.com/fabelx/go-solc-select/pkg/trust"
+	ver "github.com/fabelx/go-solc-select/pkg/versions"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	config.SolcDir = filepath.Join(config.HomeDir, ".test-gsolc-select")
+	config.SolcArtifacts = filepath.Join(config.SolcDir, "artifacts")
+	config.SolcLocks = filepath.Join(config.SolcDir, "locks")
+	config.SolcCache = filepath.Join(config.SolcDir, "cache")
+	config.KnownHashesPath = filepath.Join(config.SolcDir, "known-hashes.json")
+	err := os.MkdirAll(config.SolcArtifacts, 0755)
+	if err != nil {
+		log.Fatalf("Failed to run tests during setup. Error: %v", err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(config.SolcDir)
+	os.Exit(code)
+}
+
+// install Lays out a fake compiler version in artifacts, with a manifest if required
+func install(t *testing.T, version string, withManifest bool) string {
+	name := fmt.Sprintf("solc-%s", version)
+	folder := filepath.Join(config.SolcArtifacts, name)
+	os.MkdirAll(folder, 0755)
+	os.WriteFile(filepath.Join(folder, name), []byte(name), 0755)
+	if withManifest {
+		m, err := manifest.Create(folder, buildOf(version, name), "", config.LinuxAmd64)
+		assert.NoError(t, err)
+		assert.NoError(t, m.Write(folder))
+	}
+
+	t.Cleanup(func() { os.RemoveAll(folder) })
+	return filepath.Join(folder, name)
+}
+
+// serveList Serves the list of builds of the versions as the only mirror
+func serveList(t *testing.T, builds ...*utils.BuildData) {
+	platform, _ := ver.GetPlatform(runtime.GOOS)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case fmt.Sprintf("/%s/list.json", platform.GetName()):
+			json.NewEncoder(w).Encode(utils.ResponseData{Builds: builds})
+		default:
+			json.NewEncoder(w).Encode(utils.ResponseData{})
+		}
+	}))
+
+	solcMirrors, oldSolcListMirrors := config.SolcMirrors, config.OldSolcListMirrors
+	config.SolcMirrors = []string{server.URL}
+	config.OldSolcListMirrors = []string{server.URL + "/old/list.json"}
+	t.Cleanup(func() {
+		server.Close()
+		config.SolcMirrors, config.OldSolcListMirrors = solcMirrors, oldSolcListMirrors
+	})
+}
+
+// pin Pins the checksums of the build until the test ends
+func pin(t *testing.T, build *utils.BuildData) {
+	platform, _ := ver.GetPlatform(runtime.GOOS)
+	err := trust.Remember(context.Background(), platform.GetName(), []*utils.BuildData{build})
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.Remove(config.KnownHashesPath) })
+}
+
+// buildOf Returns the build of the version with the checksums of the data
+func buildOf(version string, data string) *utils.BuildData {
+	checksum := utils.NewChecksum()
+	checksum.Write([]byte(data))
+	return &utils.BuildData{Version: version, Sha256: checksum.Sha256(), Keccak256: checksum.Keccak256()}
+}
+
+func TestVerifySolcs(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("intact version with manifest", func(t *testing.T) {
+		install(t, "0.8.0", true)
+		result, err := VerifySolc(ctx, "0.8.0")
+		assert.NoError(t, err)
+		assert.Equal(t, SourceManifest, result.Source)
+	})
+
+	t.Run("modified version with manifest", func(t *testing.T) {
+		path := install(t, "0.8.0", true)
+		os.WriteFile(path, []byte("tampered"), 0755)
+		result, err := VerifySolc(ctx, "0.8.0")
+		assert.Equal(t, &errors.ModifiedFileError{Version: "0.8.0", File: "solc-0.8.0", HashFunc: "Sha256"}, err)
+		assert.Equal(t, SourceManifest, result.Source)
+	})
+
+	t.Run("intact version without manifest", func(t *testing.T) {
+		serveList(t, buildOf("0.8.0", "solc-0.8.0"))
+		install(t, "0.8.0", false)
+		result, err := VerifySolc(ctx, "0.8.0")
+		assert.NoError(t, err)
+		assert.Equal(t, SourceList, result.Source)
+	})
+
+	t.Run("modified version without manifest", func(t *testing.T) {
+		serveList(t, buildOf("0.8.0", "solc-0.8.0"))
+		path := install(t, "0.8.0", false)
+		os.WriteFile(path, []byte("tampered"), 0755)
+		_, err := VerifySolc(ctx, "0.8.0")
+		assert.Equal(t, &errors.ModifiedFileError{Version: "0.8.0", File: "solc-0.8.0", HashFunc: "Sha256"}, err)
+	})
+
+	t.Run("unknown version without manifest", func(t *testing.T) {
+		serveList(t)
+		install(t, "0.8.0", false)
+		_, err := VerifySolc(ctx, "0.8.0")
+		assert.Equal(t, &errors.UnverifiableError{Version: "0.8.0"}, err)
+	})
+
+	t.Run("modified version with rewritten manifest", func(t *testing.T) {
+		// the binary is compared with the checksums of its build
+		path := install(t, "0.8.0", true)
+		folder := filepath.Dir(path)
+		os.WriteFile(path, []byte("tampered"), 0755)
+		m, _ := manifest.Create(folder, buildOf("0.8.0", "solc-0.8.0"), "", config.LinuxAmd64)
+		m.Write(folder)
+		_, err := VerifySolc(ctx, "0.8.0")
+		assert.Equal(t, &errors.ModifiedFileError{Version: "0.8.0", File: "solc-0.8.0", HashFunc: "Sha256"}, err)
+
+		// and with the pinned checksums, which a rewritten build can't change
+		pin(t, buildOf("0.8.0", "solc-0.8.0"))
+		m, _ = manifest.Create(folder, buildOf("0.8.0", "tampered"), "", config.LinuxAmd64)
+		m.Write(folder)
+		_, err = VerifySolc(ctx, "0.8.0")
+		assert.Equal(t, &errors.ModifiedFileError{Version: "0.8.0", File: "solc-0.8.0", HashFunc: "Sha256"}, err)
+	})
+
+	t.Run("version without manifest verified with pinned checksums", func(t *testing.T) {
+		// the list is never requested, even if it matches the modified binary
+		serveList(t, buildOf("0.8.0", "tampered"))
+		pin(t, buildOf("0.8.0", "solc-0.8.0"))
+		path := install(t, "0.8.0", false)
+		result, err := VerifySolc(ctx, "0.8.0")
+		assert.NoError(t, err)
+		assert.Equal(t, SourcePinned, result.Source)
+
+		os.WriteFile(path, []byte("tampered"), 0755)
+		_, err = VerifySolc(ctx, "0.8.0")
+		assert.Equal(t, &errors.ModifiedFileError{Version: "0.8.0", File: "solc-0.8.0", HashFunc: "Sha256"}, err)
+	})
+
+	t.Run("results in passed order", func(t *testing.T) {
+		install(t, "0.8.0", true)
+		path := install(t, "0.8.1", true)
+		os.WriteFile(path, []byte("tampered"), 0755)
+		results, err := VerifySolcs(ctx, []string{"0.8.2", "0.8.1", "0.8.0"})
+		assert.NoError(t, err)
+		assert.Len(t, results, 3)
+		assert.Equal(t, &errors.NotInstalledError{Version: "0.8.2"}, results[0].Err)
+		assert.IsType(t, &errors.ModifiedFileError{}, results[1].Err)
+		assert.NoError(t, results[2].Err)
+		assert.Len(t, Failed(results), 2)
+	})
+}
@@ -0,0 +1,187 @@
+/*
+	Copyright © 2022 Vladyslav Novotnyi <daprostovseeto@gmail.com>.
+
+	fabelx/go-solc-select is licensed under the
+	GNU Affero General Public License v3.0
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as
+    published by the Free Software Foundation, either version 3 of the
+    License, or (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+go-solc-select is a tool written in Golang for managing and switching between versions of the Solidity compiler.
+*/
+
+package versions
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/internal/utils"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cacheEntry Cached list of compilers with the validators of the response it came from
+type cacheEntry struct {
+	Url          string              `json:"url"`
+	ETag         string              `json:"etag,omitempty"`
+	LastModified string              `json:"last_modified,omitempty"`
+	FetchedAt    time.Time           `json:"fetched_at"`
+	Data         *utils.ResponseData `json:"data"`
+}
+
+// refreshKey The context key forcing revalidation of cached lists regardless of their age
+type refreshKey struct{}
+
+// noStaleKey The context key disabling the fallback to stale lists, set while other mirrors can still be tried
+type noStaleKey struct{}
+
+// Refresh Revalidates the cached lists of compilers of the platform with the server regardless of their age
+// Returns an error in the offline mode
+func Refresh(ctx context.Context, platform Platform) error {
+	_, err := platform.GetBuilds(context.WithValue(ctx, refreshKey{}, true))
+	return err
+}
+
+// cachePath Returns the path of the cache file of the list, the file is named after the hash of the url
+func cachePath(url string) string {
+	return filepath.Join(config.SolcCache, fmt.Sprintf("%x.json", sha256.Sum256([]byte(url))))
+}
+
+// readCache Returns the cached list, nil if the list isn't cached or the cache file is broken
+func readCache(url string) *cacheEntry {
+	data, err := os.ReadFile(cachePath(url))
+	if err != nil {
+		return nil
+	}
+
+	entry := &cacheEntry{}
+	if json.Unmarshal(data, entry) != nil || entry.Url != url || entry.Data == nil {
+		return nil
+	}
+
+	return entry
+}
+
+// writeCache Stores the list in the cache, the file is replaced atomically
+func writeCache(entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(config.SolcCache, 0755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(config.SolcCache, "list-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), cachePath(entry.Url))
+}
+
+// get Returns the list of compilers from the url
+//
+// The list is served from the cache while it's younger than config.CacheTTL, after that it's revalidated
+// with a conditional request. A stale list is used if the server is unreachable, unless the fallback
+// is disabled by noStaleKey. In the offline mode only the cache is used
+func get(ctx context.Context, url string) (*utils.ResponseData, error) {
+	entry := readCache(url)
+	refresh := ctx.Value(refreshKey{}) != nil
+	if config.Offline {
+		if entry == nil || refresh {
+			return nil, &errors.NotCachedError{Url: url}
+		}
+
+		return entry.Data, nil
+	}
+
+	if entry != nil && !refresh && time.Since(entry.FetchedAt) < config.CacheTTL {
+		return entry.Data, nil
+	}
+
+	header := http.Header{}
+	if entry != nil {
+		if entry.ETag != "" {
+			header.Set("If-None-Match", entry.ETag)
+		}
+
+		if entry.LastModified != "" {
+			header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	r, err := utils.Fetch(ctx, url, header)
+	if err != nil {
+		if entry != nil && ctx.Value(noStaleKey{}) == nil && ctx.Err() == nil && !refresh {
+			return entry.Data, nil
+		}
+
+		return nil, err
+	}
+
+	if r.StatusCode == http.StatusNotModified && entry != nil {
+		entry.FetchedAt = time.Now()
+		writeCache(entry)
+		return entry.Data, nil
+	}
+
+	respData := utils.ResponseData{}
+	err = json.Unmarshal(r.Body, &respData)
+	if err != nil {
+		return nil, err
+	}
+
+	// The cache is an optimization, failing to write it doesn't fail the request
+	writeCache(&cacheEntry{
+		Url:          url,
+		ETag:         r.Header.Get("ETag"),
+		LastModified: r.Header.Get("Last-Modified"),
+		FetchedAt:    time.Now(),
+		Data:         &respData,
+	})
+
+	return &respData, nil
+}
+
+// getStale Returns the cached list of the url regardless of its age, nil if the list isn't cached
+// or the cached lists must be revalidated
+func getStale(ctx context.Context, url string) *utils.ResponseData {
+	if ctx.Value(refreshKey{}) != nil {
+		return nil
+	}
+
+	entry := readCache(url)
+	if entry == nil {
+		return nil
+	}
+
+	return entry.Data
+}
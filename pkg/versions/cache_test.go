@@ -0,0 +1,150 @@
+package versions
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/fabelx/go-solc-select/internal/errors"
+	"github.com/fabelx/go-solc-select/internal/utils"
+	"github.com/fabelx/go-solc-select/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// listServer Serves a list of compilers with an ETag, counts requests and the requests answered with 304
+type listServer struct {
+	*httptest.Server
+	requests    int
+	notModified int
+}
+
+func newListServer(t *testing.T) *listServer {
+	s := &listServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			s.notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		json.NewEncoder(w).Encode(utils.ResponseData{Releases: map[string]string{"0.8.0": "solc-v0.8.0"}})
+	}))
+	t.Cleanup(func() {
+		s.Close()
+		os.RemoveAll(config.SolcCache)
+	})
+
+	return s
+}
+
+func TestGetCache(t *testing.T) {
+	expected := &utils.ResponseData{Releases: map[string]string{"0.8.0": "solc-v0.8.0"}}
+
+	t.Run("serves fresh lists from the cache", func(t *testing.T) {
+		server := newListServer(t)
+		for i := 0; i < 3; i++ {
+			result, err := get(context.Background(), server.URL)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, result)
+		}
+
+		assert.Equal(t, 1, server.requests)
+	})
+
+	t.Run("revalidates stale lists", func(t *testing.T) {
+		server := newListServer(t)
+		ttl := config.CacheTTL
+		config.CacheTTL = 0
+		defer func() { config.CacheTTL = ttl }()
+
+		get(context.Background(), server.URL)
+		result, err := get(context.Background(), server.URL)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+		assert.Equal(t, 2, server.requests)
+		assert.Equal(t, 1, server.notModified)
+	})
+
+	t.Run("uses stale lists if the server is unreachable", func(t *testing.T) {
+		server := newListServer(t)
+		get(context.Background(), server.URL)
+		server.Close()
+
+		ttl, retries := config.CacheTTL, config.HttpRetries
+		config.CacheTTL, config.HttpRetries = 0, 0
+		defer func() { config.CacheTTL, config.HttpRetries = ttl, retries }()
+		result, err := get(context.Background(), server.URL)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("uses only the cache in the offline mode", func(t *testing.T) {
+		server := newListServer(t)
+		config.Offline = true
+		defer func() { config.Offline = false }()
+
+		_, err := get(context.Background(), server.URL)
+		assert.Equal(t, &errors.NotCachedError{Url: server.URL}, err)
+
+		config.Offline = false
+		get(context.Background(), server.URL)
+		config.Offline = true
+		result, err := get(context.Background(), server.URL)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+		assert.Equal(t, 1, server.requests)
+	})
+
+	t.Run("refresh revalidates fresh lists", func(t *testing.T) {
+		server := newListServer(t)
+		solcMirrors := config.SolcMirrors
+		config.SolcMirrors = []string{server.URL}
+		defer func() { config.SolcMirrors = solcMirrors }()
+
+		platform := &MacPlatform{Name: config.MacosxAmd64}
+		_, err := platform.GetAvailableVersions(context.Background())
+		assert.NoError(t, err)
+		err = Refresh(context.Background(), platform)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, server.requests)
+		assert.Equal(t, 1, server.notModified)
+	})
+
+	t.Run("ignores broken cache files", func(t *testing.T) {
+		server := newListServer(t)
+		get(context.Background(), server.URL)
+		os.WriteFile(cachePath(server.URL), []byte("{"), 0644)
+		result, err := get(context.Background(), server.URL)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+		assert.Equal(t, 2, server.requests)
+	})
+
+	t.Run("fails over to the next mirror before using stale lists", func(t *testing.T) {
+		down := newListServer(t)
+		up := newListServer(t)
+		stale := &utils.ResponseData{Releases: map[string]string{"0.7.0": "solc-v0.7.0"}}
+		assert.NoError(t, writeCache(&cacheEntry{Url: down.URL, FetchedAt: time.Now().Add(-2 * config.CacheTTL), Data: stale}))
+		down.Close()
+
+		retries := config.HttpRetries
+		config.HttpRetries = 0
+		defer func() { config.HttpRetries = retries }()
+		result, err := getFirst(context.Background(), []string{down.URL, up.URL})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+		assert.Equal(t, 1, up.requests)
+
+		// Stale lists are used only if no mirror responded
+		up.Close()
+		os.Remove(cachePath(up.URL))
+		result, err = getFirst(context.Background(), []string{down.URL, up.URL})
+		assert.NoError(t, err)
+		assert.Equal(t, stale, result)
+	})
+}
@@ -22,10 +22,13 @@ go-solc-select is a tool written in Golang for managing and switching between ve
 package versions
 
 import (
+	"fmt"
 	"github.com/Masterminds/semver"
 	"github.com/fabelx/go-solc-select/internal/errors"
 	"github.com/fabelx/go-solc-select/pkg/config"
+	"github.com/fabelx/go-solc-select/pkg/pragma"
 	"sort"
+	"strings"
 )
 
 // GetPlatform Returns a representation of the current operating system platform
@@ -52,3 +55,65 @@ func SortVersions(versions map[string]string) []*semver.Version {
 	sort.Sort(semver.Collection(vs))
 	return vs
 }
+
+// Latest The keyword which resolves to the highest version
+const Latest = "latest"
+
+// ParseConstraint Returns the expression parsed from the passed constraint
+//
+// Constraints are matched the same way as Solidity version pragmas (pkg/pragma), e.g. ^0.7 is 0.7.x.
+// In addition to the space separated constraints (>=0.6 <0.7), comma separated ones are accepted (>=0.6, <0.7)
+func ParseConstraint(constraint string) (*pragma.Expression, error) {
+	expression, err := pragma.Parse(strings.Replace(constraint, ",", " ", -1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid version constraint '%s'", constraint)
+	}
+
+	return expression, nil
+}
+
+// Resolve Returns the highest version satisfying the constraint
+//
+// The constraint is either an exact version (0.8.19), `latest` or a semver constraint (^0.8.0, >=0.6 <0.7, 0.7.x)
+func Resolve(constraint string, versions map[string]string) (string, error) {
+	if versions[constraint] != "" {
+		return constraint, nil
+	}
+
+	matches, err := ResolveAll(constraint, versions)
+	if err != nil {
+		return "", err
+	}
+
+	return matches[len(matches)-1], nil
+}
+
+// ResolveAll Returns all versions satisfying the constraint in ascending order
+func ResolveAll(constraint string, versions map[string]string) ([]string, error) {
+	sorted := SortVersions(versions)
+	if constraint == Latest {
+		if len(sorted) == 0 {
+			return nil, &errors.NoMatchingVersionError{Constraint: constraint}
+		}
+
+		return []string{sorted[len(sorted)-1].Original()}, nil
+	}
+
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	for _, version := range sorted {
+		if c.Matches(version) {
+			matches = append(matches, version.Original())
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, &errors.NoMatchingVersionError{Constraint: constraint}
+	}
+
+	return matches, nil
+}
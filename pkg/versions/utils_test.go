@@ -78,3 +78,141 @@ func TestSortVersions(t *testing.T) {
 		assert.Equal(t, expected, result)
 	})
 }
+
+func TestResolve(t *testing.T) {
+	versions := map[string]string{
+		"0.4.26": "0.4.26",
+		"0.7.6":  "0.7.6",
+		"0.8.3":  "0.8.3",
+		"0.8.19": "0.8.19",
+	}
+	testCases := []struct {
+		input    string
+		expected string
+		err      error
+	}{
+		{
+			input:    "0.8.3",
+			expected: "0.8.3",
+			err:      nil,
+		},
+		{
+			input:    "^0.8.0",
+			expected: "0.8.19",
+			err:      nil,
+		},
+		{
+			input:    "<0.8.0",
+			expected: "0.7.6",
+			err:      nil,
+		},
+		{
+			input:    "0.4.x",
+			expected: "0.4.26",
+			err:      nil,
+		},
+		{
+			input:    "^0.7",
+			expected: "0.7.6",
+			err:      nil,
+		},
+		{
+			input:    ">=0.4 <0.8",
+			expected: "0.7.6",
+			err:      nil,
+		},
+		{
+			input:    ">= 0.8.0, < 0.8.10",
+			expected: "0.8.3",
+			err:      nil,
+		},
+		{
+			input:    "0.4.0 - 0.7.0",
+			expected: "0.4.26",
+			err:      nil,
+		},
+		{
+			input:    "0.4.x || ^0.7.0",
+			expected: "0.7.6",
+			err:      nil,
+		},
+		{
+			input:    "latest",
+			expected: "0.8.19",
+			err:      nil,
+		},
+		{
+			input:    "~0.6.0",
+			expected: "",
+			err:      &errors.NoMatchingVersionError{Constraint: "~0.6.0"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.input, func(t *testing.T) {
+			result, err := Resolve(testCase.input, versions)
+			assert.Equal(t, testCase.err, err)
+			assert.Equal(t, testCase.expected, result)
+		})
+	}
+
+	t.Run("test invalid constraint", func(t *testing.T) {
+		_, err := Resolve("foo", versions)
+		assert.EqualError(t, err, "invalid version constraint 'foo'")
+	})
+}
+
+func TestResolveAll(t *testing.T) {
+	versions := map[string]string{
+		"0.4.26": "0.4.26",
+		"0.7.6":  "0.7.6",
+		"0.8.3":  "0.8.3",
+		"0.8.19": "0.8.19",
+	}
+	testCases := []struct {
+		input    string
+		expected []string
+		err      error
+	}{
+		{
+			input:    "^0.8",
+			expected: []string{"0.8.3", "0.8.19"},
+			err:      nil,
+		},
+		{
+			input:    "<0.8",
+			expected: []string{"0.4.26", "0.7.6"},
+			err:      nil,
+		},
+		{
+			input:    "latest",
+			expected: []string{"0.8.19"},
+			err:      nil,
+		},
+		{
+			input:    "^0.5",
+			expected: nil,
+			err:      &errors.NoMatchingVersionError{Constraint: "^0.5"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.input, func(t *testing.T) {
+			result, err := ResolveAll(testCase.input, versions)
+			assert.Equal(t, testCase.err, err)
+			assert.Equal(t, testCase.expected, result)
+		})
+	}
+
+	t.Run("test caret of 0.0.x matches as in pragmas", func(t *testing.T) {
+		// The Solidity compiler accepts ^0.0.3 as >=0.0.3 <0.1.0, constraints follow it
+		result, err := ResolveAll("^0.0.3", map[string]string{"0.0.2": "0.0.2", "0.0.3": "0.0.3", "0.0.4": "0.0.4", "0.1.0": "0.1.0"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"0.0.3", "0.0.4"}, result)
+	})
+
+	t.Run("test latest of no versions", func(t *testing.T) {
+		_, err := ResolveAll("latest", map[string]string{})
+		assert.Equal(t, &errors.NoMatchingVersionError{Constraint: "latest"}, err)
+	})
+}
@@ -22,7 +22,8 @@ go-solc-select is a tool written in Golang for managing and switching between ve
 package versions
 
 import (
-	"encoding/json"
+	"context"
+	goerrors "errors"
 	"fmt"
 	"github.com/fabelx/go-solc-select/internal/errors"
 	"github.com/fabelx/go-solc-select/internal/utils"
@@ -34,9 +35,11 @@ import (
 )
 
 type Platform interface {
-	GetAvailableVersions() (map[string]string, error)
-	GetBuilds() ([]*utils.BuildData, error)
+	GetName() string
+	GetAvailableVersions(ctx context.Context) (map[string]string, error)
+	GetBuilds(ctx context.Context) ([]*utils.BuildData, error)
 	GenerateBuildUrl(build *utils.BuildData) string
+	GenerateBuildUrls(build *utils.BuildData) []string
 }
 
 type LinuxPlatform struct {
@@ -51,29 +54,29 @@ type WindowsPlatform struct {
 	Name string `json:"name"`
 }
 
-func get(url string) (*utils.ResponseData, error) {
-	data, err := utils.Get(url)
-	if err != nil {
-		return nil, err
-	}
+// GetName Returns the name of the platform in the repository, e.g. linux-amd64
+func (r *LinuxPlatform) GetName() string {
+	return r.Name
+}
 
-	respData := utils.ResponseData{}
-	err = json.Unmarshal(data, &respData)
-	if err != nil {
-		return nil, err
-	}
+// GetName Returns the name of the platform in the repository, e.g. macosx-amd64
+func (r *MacPlatform) GetName() string {
+	return r.Name
+}
 
-	return &respData, nil
+// GetName Returns the name of the platform in the repository, e.g. windows-amd64
+func (r *WindowsPlatform) GetName() string {
+	return r.Name
 }
 
 // GetAvailableVersions Returns an array of compiler versions for linux
-func (r *LinuxPlatform) GetAvailableVersions() (map[string]string, error) {
-	versions, err := getVersions(fmt.Sprintf("%s/%s/list.json", config.SoliditylangUrl, r.Name))
+func (r *LinuxPlatform) GetAvailableVersions(ctx context.Context) (map[string]string, error) {
+	versions, err := getVersions(ctx, listUrls(r.Name))
 	if err != nil {
 		return nil, err
 	}
 
-	oldVersions, err := getVersions(config.OldSolcListUrl)
+	oldVersions, err := getVersions(ctx, config.OldSolcListMirrors)
 	if err != nil {
 		return nil, err
 	}
@@ -86,23 +89,23 @@ func (r *LinuxPlatform) GetAvailableVersions() (map[string]string, error) {
 }
 
 // GetAvailableVersions Returns an array of compiler versions for mac
-func (r *MacPlatform) GetAvailableVersions() (map[string]string, error) {
-	return getVersions(fmt.Sprintf("%s/%s/list.json", config.SoliditylangUrl, r.Name))
+func (r *MacPlatform) GetAvailableVersions(ctx context.Context) (map[string]string, error) {
+	return getVersions(ctx, listUrls(r.Name))
 }
 
 // GetAvailableVersions Returns an array of compiler versions for windows
-func (r *WindowsPlatform) GetAvailableVersions() (map[string]string, error) {
-	return getVersions(fmt.Sprintf("%s/%s/list.json", config.SoliditylangUrl, r.Name))
+func (r *WindowsPlatform) GetAvailableVersions(ctx context.Context) (map[string]string, error) {
+	return getVersions(ctx, listUrls(r.Name))
 }
 
 // GetBuilds Returns an array of meta information about compilers for linux
-func (r *LinuxPlatform) GetBuilds() ([]*utils.BuildData, error) {
-	builds, err := getBuilds(fmt.Sprintf("%s/%s/list.json", config.SoliditylangUrl, r.Name))
+func (r *LinuxPlatform) GetBuilds(ctx context.Context) ([]*utils.BuildData, error) {
+	builds, err := getBuilds(ctx, listUrls(r.Name))
 	if err != nil {
 		return nil, err
 	}
 
-	oldBuilds, err := getBuilds(config.OldSolcListUrl)
+	oldBuilds, err := getBuilds(ctx, config.OldSolcListMirrors)
 	if err != nil {
 		return nil, err
 	}
@@ -113,40 +116,86 @@ func (r *LinuxPlatform) GetBuilds() ([]*utils.BuildData, error) {
 }
 
 // GetBuilds Returns an array of meta information about compilers for mac
-func (r *MacPlatform) GetBuilds() ([]*utils.BuildData, error) {
-	return getBuilds(fmt.Sprintf("%s/%s/list.json", config.SoliditylangUrl, r.Name))
+func (r *MacPlatform) GetBuilds(ctx context.Context) ([]*utils.BuildData, error) {
+	return getBuilds(ctx, listUrls(r.Name))
 }
 
 // GetBuilds Returns an array of meta information about compilers for windows
-func (r *WindowsPlatform) GetBuilds() ([]*utils.BuildData, error) {
-	return getBuilds(fmt.Sprintf("%s/%s/list.json", config.SoliditylangUrl, r.Name))
+func (r *WindowsPlatform) GetBuilds(ctx context.Context) ([]*utils.BuildData, error) {
+	return getBuilds(ctx, listUrls(r.Name))
 }
 
-// GenerateBuildUrl Returns the url of solc compiler file(s) for linux
+// GenerateBuildUrl Returns the url of solc compiler file(s) for linux from the first mirror
 func (r *LinuxPlatform) GenerateBuildUrl(build *utils.BuildData) string {
-	if utils.IsOldLinuxVersion(build.Version) {
-		return fmt.Sprintf("%s/%s", config.OldSolcUrl, build.Name)
-	}
-	return fmt.Sprintf("%s/%s/%s", config.SoliditylangUrl, r.Name, build.Path)
+	return r.GenerateBuildUrls(build)[0]
 }
 
-// GenerateBuildUrl Returns the url of solc compiler file(s) for mac
+// GenerateBuildUrl Returns the url of solc compiler file(s) for mac from the first mirror
 func (r *MacPlatform) GenerateBuildUrl(build *utils.BuildData) string {
-	return fmt.Sprintf("%s/%s/%s", config.SoliditylangUrl, r.Name, build.Path)
+	return r.GenerateBuildUrls(build)[0]
 }
 
-// GenerateBuildUrl Returns the url of solc compiler file(s) for windows
+// GenerateBuildUrl Returns the url of solc compiler file(s) for windows from the first mirror
 func (r *WindowsPlatform) GenerateBuildUrl(build *utils.BuildData) string {
-	return fmt.Sprintf("%s/%s/%s", config.SoliditylangUrl, r.Name, build.Path)
+	return r.GenerateBuildUrls(build)[0]
+}
+
+// GenerateBuildUrls Returns the urls of solc compiler file(s) for linux from all mirrors in order
+func (r *LinuxPlatform) GenerateBuildUrls(build *utils.BuildData) []string {
+	if utils.IsOldLinuxVersion(build.Version) {
+		var urls []string
+		for _, mirror := range config.OldSolcMirrors {
+			urls = append(urls, fmt.Sprintf("%s/%s", mirror, build.Name))
+		}
+
+		return urls
+	}
+
+	return buildUrls(r.Name, build)
+}
+
+// GenerateBuildUrls Returns the urls of solc compiler file(s) for mac from all mirrors in order
+func (r *MacPlatform) GenerateBuildUrls(build *utils.BuildData) []string {
+	return buildUrls(r.Name, build)
+}
+
+// GenerateBuildUrls Returns the urls of solc compiler file(s) for windows from all mirrors in order
+func (r *WindowsPlatform) GenerateBuildUrls(build *utils.BuildData) []string {
+	return buildUrls(r.Name, build)
+}
+
+// listUrls Returns the urls of the list of compilers for the platform from all mirrors in order
+func listUrls(platform string) []string {
+	var urls []string
+	for _, mirror := range config.SolcMirrors {
+		urls = append(urls, fmt.Sprintf("%s/%s/list.json", mirror, platform))
+	}
+
+	return urls
+}
+
+// buildUrls Returns the urls of the compiler file(s) for the platform from all mirrors in order
+func buildUrls(platform string, build *utils.BuildData) []string {
+	var urls []string
+	for _, mirror := range config.SolcMirrors {
+		urls = append(urls, fmt.Sprintf("%s/%s/%s", mirror, platform, build.Path))
+	}
+
+	return urls
 }
 
 // GetInstalled Returns installed versions on system
-// GetInstalled ignores ErrBadPattern error
+// GetInstalled ignores ErrBadPattern error and folders without the compiler binary
 func GetInstalled() map[string]string {
 	matches, _ := filepath.Glob(filepath.Join(config.SolcArtifacts, "solc-*"))
 	versions := make(map[string]string)
 	for _, path := range matches {
-		version := strings.Replace(filepath.Base(path), "solc-", "", 1)
+		name := filepath.Base(path)
+		if info, err := os.Stat(filepath.Join(path, name)); err != nil || info.IsDir() {
+			continue
+		}
+
+		version := strings.Replace(name, "solc-", "", 1)
 		versions[version] = version
 	}
 
@@ -154,39 +203,107 @@ func GetInstalled() map[string]string {
 }
 
 // GetAvailable Returns all installable versions of the solc compiler for the current operating system platform
-func GetAvailable() (map[string]string, error) {
+func GetAvailable(ctx context.Context) (map[string]string, error) {
 	platform, err := GetPlatform(runtime.GOOS)
 	if err != nil {
 		return nil, err
 	}
 
-	return platform.GetAvailableVersions()
+	return platform.GetAvailableVersions(ctx)
 }
 
 // GetCurrent Returns current version on system
+//
+// The version set by the `SOLC_VERSION` environment variable takes precedence over the version
+// pinned by the nearest `.solc-version` file, which in turn takes precedence over the global version
 func GetCurrent() (string, error) {
-	// Getting the compiler version from a file where the version is specified
-	data, err := os.ReadFile(config.CurrentVersionFilePath)
+	if version := os.Getenv(config.SolcVersionEnv); version != "" {
+		return checkInstalled(version)
+	}
+
+	path, err := GetCurrentVersionFilePath()
 	if err != nil {
 		return "", err
 	}
 
-	version := string(data)
+	return readVersionFile(path)
+}
 
-	if version != "" {
-		installedVersions := GetInstalled()
-		// Checking if the compiler is installed on the host
-		if installedVersions[version] == "" {
-			return "", &errors.NotInstalledError{Version: version}
+// GetGlobal Returns version from the `global-version` file
+func GetGlobal() (string, error) {
+	return readVersionFile(config.CurrentVersionFilePath)
+}
+
+// GetCurrentVersionFilePath Returns the path of the file which determines the current version
+//
+// Looks for the nearest `.solc-version` file starting from the working directory,
+// falls back to the `global-version` file
+func GetCurrentVersionFilePath() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	if path := FindLocalVersionFile(dir); path != "" {
+		return path, nil
+	}
+
+	return config.CurrentVersionFilePath, nil
+}
+
+// FindLocalVersionFile Returns the path of the nearest `.solc-version` file walking up from the passed directory
+// Returns an empty string if there is no such file
+func FindLocalVersionFile(dir string) string {
+	return FindLocalVersionFileWithin(dir, "")
+}
+
+// FindLocalVersionFileWithin Returns the path of the nearest `.solc-version` file walking up from the passed directory,
+// the walk stops at the root directory (inclusive) if the root isn't empty
+// Returns an empty string if there is no such file
+func FindLocalVersionFileWithin(dir string, root string) string {
+	for {
+		path := filepath.Join(dir, config.LocalVersionFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir || dir == root {
+			return ""
 		}
 
-		return version, nil
+		dir = parent
+	}
+}
+
+// readVersionFile Returns version specified in the file
+func readVersionFile(path string) (string, error) {
+	// Getting the compiler version from a file where the version is specified
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	version := strings.TrimSpace(string(data))
+
+	if version != "" {
+		return checkInstalled(version)
 	} else {
 		return "", &errors.NoCompilerSelected{}
 	}
 
 }
 
+// checkInstalled Returns the version if the compiler is installed on the host
+func checkInstalled(version string) (string, error) {
+	installedVersions := GetInstalled()
+	if installedVersions[version] == "" {
+		return "", &errors.NotInstalledError{Version: version}
+	}
+
+	return version, nil
+}
+
 // GetBuild Returns compiler meta information for a specific version
 func GetBuild(builds []*utils.BuildData, version string) (*utils.BuildData, error) {
 	for _, build := range builds {
@@ -198,9 +315,9 @@ func GetBuild(builds []*utils.BuildData, version string) (*utils.BuildData, erro
 	return nil, &errors.UnknownVersionError{Version: version}
 }
 
-// getVersions Returns an array of compiler versions
-func getVersions(url string) (map[string]string, error) {
-	respData, err := get(url)
+// getVersions Returns an array of compiler versions from the first mirror which responded
+func getVersions(ctx context.Context, urls []string) (map[string]string, error) {
+	respData, err := getFirst(ctx, urls)
 	if err != nil {
 		return nil, err
 	}
@@ -208,12 +325,41 @@ func getVersions(url string) (map[string]string, error) {
 	return respData.Releases, nil
 }
 
-// getBuilds Returns an array of meta information about compilers
-func getBuilds(url string) ([]*utils.BuildData, error) {
-	respData, err := get(url)
+// getBuilds Returns an array of meta information about compilers from the first mirror which responded
+func getBuilds(ctx context.Context, urls []string) ([]*utils.BuildData, error) {
+	respData, err := getFirst(ctx, urls)
 	if err != nil {
 		return nil, err
 	}
 
 	return respData.Builds, nil
 }
+
+// getFirst Requests the lists of compilers from the urls in order, returns the first received list
+// or the error of the last url if none responded
+//
+// A stale cached list is used only if none of the urls responded, so an unreachable mirror doesn't prevent
+// failing over to the next one
+func getFirst(ctx context.Context, urls []string) (*utils.ResponseData, error) {
+	failoverCtx := context.WithValue(ctx, noStaleKey{}, true)
+	err := goerrors.New("no mirrors configured")
+	for _, url := range urls {
+		var respData *utils.ResponseData
+		respData, err = get(failoverCtx, url)
+		if err == nil {
+			return respData, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	for _, url := range urls {
+		if respData := getStale(ctx, url); respData != nil {
+			return respData, nil
+		}
+	}
+
+	return nil, err
+}
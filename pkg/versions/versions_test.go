@@ -1,6 +1,7 @@
 package versions
 
 import (
+	"context"
 	"fmt"
 	"github.com/fabelx/go-solc-select/internal/errors"
 	"github.com/fabelx/go-solc-select/internal/utils"
@@ -10,6 +11,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -37,6 +39,7 @@ func TestMain(m *testing.M) {
 func setup() error {
 	// creates dirs for testing
 	config.SolcDir = filepath.Join(config.HomeDir, ".test-gsolc-select")
+	config.SolcCache = filepath.Join(config.SolcDir, "cache")
 	config.SolcArtifacts = filepath.Join(config.SolcDir, "artifacts")
 	err := os.MkdirAll(config.SolcArtifacts, 0755)
 	if err != nil {
@@ -70,9 +73,17 @@ func shutdown() {
 func TestGetInstalled(t *testing.T) {
 	result := GetInstalled()
 	assert.Equal(t, testVersions, result)
+
+	// folder left by an interrupted installation isn't reported as installed
+	dirPath := filepath.Join(config.SolcArtifacts, "solc-0.4.0")
+	os.Mkdir(dirPath, 0755)
+	defer os.RemoveAll(dirPath)
+	result = GetInstalled()
+	assert.Equal(t, testVersions, result)
 }
 
 func TestGetCurrent(t *testing.T) {
+	wd, _ := os.Getwd()
 	testCases := []struct {
 		name     string
 		expected string
@@ -120,6 +131,45 @@ func TestGetCurrent(t *testing.T) {
 			},
 			after: func() {},
 		},
+		{
+			name:     "test local version takes precedence over global version",
+			expected: "0.5.1",
+			err:      nil,
+			before: func() {
+				dir := filepath.Join(config.SolcDir, "project", "contracts")
+				os.MkdirAll(dir, 0755)
+				os.WriteFile(filepath.Join(config.SolcDir, "project", config.LocalVersionFileName), []byte("0.5.1\n"), 0644)
+				os.Chdir(dir)
+			},
+			after: func() {
+				os.Chdir(wd)
+				os.RemoveAll(filepath.Join(config.SolcDir, "project"))
+			},
+		},
+		{
+			name:     "test environment variable takes precedence over global version",
+			expected: "0.6.7",
+			err:      nil,
+			before: func() {
+				os.Setenv(config.SolcVersionEnv, "0.6.7")
+			},
+			after: func() {
+				os.Unsetenv(config.SolcVersionEnv)
+			},
+		},
+		{
+			name:     "test not installed version, but specified by environment variable",
+			expected: "",
+			err: &errors.NotInstalledError{
+				Version: notInstalledVersion,
+			},
+			before: func() {
+				os.Setenv(config.SolcVersionEnv, notInstalledVersion)
+			},
+			after: func() {
+				os.Unsetenv(config.SolcVersionEnv)
+			},
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -134,9 +184,53 @@ func TestGetCurrent(t *testing.T) {
 	}
 }
 
+func TestFindLocalVersionFile(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	err := os.MkdirAll(nested, 0755)
+	assert.NoError(t, err)
+
+	t.Run("test no local version file", func(t *testing.T) {
+		// A file above the temporary directory belongs to the host and is out of the test's control
+		path := FindLocalVersionFile(nested)
+		assert.False(t, strings.HasPrefix(path, root+string(filepath.Separator)), "unexpected file %s", path)
+	})
+
+	t.Run("test nearest local version file", func(t *testing.T) {
+		path := filepath.Join(root, config.LocalVersionFileName)
+		os.WriteFile(path, []byte(testCurrentVersion), 0644)
+		assert.Equal(t, path, FindLocalVersionFile(nested))
+
+		nearestPath := filepath.Join(root, "a", config.LocalVersionFileName)
+		os.WriteFile(nearestPath, []byte(testCurrentVersion), 0644)
+		assert.Equal(t, nearestPath, FindLocalVersionFile(nested))
+	})
+}
+
+func TestFindLocalVersionFileWithin(t *testing.T) {
+	home := t.TempDir()
+	project := filepath.Join(home, "project")
+	nested := filepath.Join(project, "contracts")
+	err := os.MkdirAll(nested, 0755)
+	assert.NoError(t, err)
+	os.WriteFile(filepath.Join(home, config.LocalVersionFileName), []byte(testCurrentVersion), 0644)
+
+	t.Run("test local version file above the root is ignored", func(t *testing.T) {
+		assert.Equal(t, "", FindLocalVersionFileWithin(nested, project))
+		assert.Equal(t, "", FindLocalVersionFileWithin(project, project))
+	})
+
+	t.Run("test local version file within the root", func(t *testing.T) {
+		path := filepath.Join(project, config.LocalVersionFileName)
+		os.WriteFile(path, []byte(testCurrentVersion), 0644)
+		assert.Equal(t, path, FindLocalVersionFileWithin(nested, project))
+		assert.Equal(t, path, FindLocalVersionFileWithin(project, project))
+	})
+}
+
 func TestGetAvailable(t *testing.T) {
 	expectedType := map[string]string{}
-	result, err := GetAvailable()
+	result, err := GetAvailable(context.Background())
 
 	assert.NoError(t, err)
 	assert.IsType(t, expectedType, result)
@@ -148,7 +242,7 @@ func TestGetAvailable(t *testing.T) {
 func TestLinuxGetAvailableVersions(t *testing.T) {
 	expected := []string{"0.4.10", "0.4.0", "0.8.13"}
 	platform := &LinuxPlatform{Name: config.LinuxAmd64}
-	result, _ := platform.GetAvailableVersions()
+	result, _ := platform.GetAvailableVersions(context.Background())
 	for _, key := range expected {
 		assert.Contains(t, result, key)
 	}
@@ -157,7 +251,7 @@ func TestLinuxGetAvailableVersions(t *testing.T) {
 func TestMacGetAvailableVersions(t *testing.T) {
 	expected := []string{"0.3.6", "0.5.17", "0.8.13"}
 	platform := &MacPlatform{Name: config.MacosxAmd64}
-	result, _ := platform.GetAvailableVersions()
+	result, _ := platform.GetAvailableVersions(context.Background())
 	for _, key := range expected {
 		assert.Contains(t, result, key)
 	}
@@ -166,7 +260,7 @@ func TestMacGetAvailableVersions(t *testing.T) {
 func TestWindowsGetAvailableVersions(t *testing.T) {
 	expected := []string{"0.4.1", "0.5.17", "0.8.13"}
 	platform := &WindowsPlatform{Name: config.WindowsAmd64}
-	result, _ := platform.GetAvailableVersions()
+	result, _ := platform.GetAvailableVersions(context.Background())
 	for _, key := range expected {
 		assert.Contains(t, result, key)
 	}
@@ -197,7 +291,7 @@ func TestGetBuilds(t *testing.T) {
 
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			result, err := testCase.platform.GetBuilds()
+			result, err := testCase.platform.GetBuilds(context.Background())
 			assert.NoError(t, err)
 			assert.IsType(t, testCase.expected, result)
 		})
@@ -275,7 +369,7 @@ func TestGet(t *testing.T) {
 	}
 
 	for _, url := range urls {
-		result, err := get(url)
+		result, err := get(context.Background(), url)
 		assert.NoError(t, err)
 		assert.IsType(t, expected, result)
 	}